@@ -0,0 +1,208 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/gooid/gl/egl/internal/backend"
+)
+
+// funcTable is the table of core EGL entry points the package calls. The
+// default, cBackend, calls libEGL; egltest installs a fake through
+// internal/backend to test EGLContext logic without a GPU.
+//
+// The methods mirror the EGL functions of the same name. Functions
+// returning EGLBoolean report it as ok, and failures are described by the
+// next GetError. Attribute lists are terminated by NONE or are nil.
+// Extension entry points are resolved with GetProcAddress, but only from
+// libEGL, see Procs.
+type funcTable interface {
+	GetDisplay(nd NativeDisplay) Display
+	Initialize(d Display) (major, minor EGLint, ok bool)
+	Terminate(d Display) bool
+	QueryString(d Display, name int) (string, bool)
+
+	GetConfigs(d Display, confs []Config) (int, bool)
+	ChooseConfig(d Display, attribs []EGLint, confs []Config) (int, bool)
+	GetConfigAttrib(d Display, conf Config, attr int) (int, bool)
+
+	CreateWindowSurface(d Display, conf Config, win NativeWindow, attribs []EGLint) Surface
+	CreatePbufferSurface(d Display, conf Config, attribs []EGLint) Surface
+	CreatePixmapSurface(d Display, conf Config, pixmap NativePixmap, attribs []EGLint) Surface
+	CreatePbufferFromClientBuffer(d Display, buftyp uint, conf Config, buf ClientBuffer, attribs []EGLint) Surface
+	DestroySurface(d Display, s Surface) bool
+	QuerySurface(d Display, s Surface, attr int) (EGLint, bool)
+	SurfaceAttrib(d Display, s Surface, attr int, val int) bool
+	BindTexImage(d Display, s Surface, buf int) bool
+	ReleaseTexImage(d Display, s Surface, buf int) bool
+	SwapInterval(d Display, inv int) bool
+
+	CreateContext(d Display, conf Config, shared Context, attribs []EGLint) Context
+	DestroyContext(d Display, c Context) bool
+	MakeCurrent(d Display, draw Surface, read Surface, c Context) bool
+	QueryContext(d Display, c Context, attr int) (EGLint, bool)
+	GetCurrentSurface(readdraw int) Surface
+	GetCurrentDisplay() Display
+
+	CopyBuffers(d Display, s Surface, target NativePixmap) bool
+	SwapBuffers(d Display, s Surface) bool
+
+	BindAPI(api uint) bool
+	QueryAPI() uint
+	WaitNative(engine int) bool
+	WaitClient() bool
+	WaitGL() bool
+	ReleaseThread() bool
+	GetError() Error
+
+	GetProcAddress(name string) unsafe.Pointer
+}
+
+var (
+	tableMu sync.RWMutex
+	table   funcTable = cBackend{}
+)
+
+func init() {
+	backend.Set = func(b interface{}) (restore func()) {
+		var t funcTable
+		if b != nil {
+			t = b.(funcTable)
+		}
+		old := setBackend(t)
+		return func() { setBackend(old) }
+	}
+}
+
+// setBackend routes the EGL calls of the package to t, nil for libEGL,
+// and returns the previous table. What is cached about displays belongs
+// to the previous table and is dropped.
+func setBackend(t funcTable) funcTable {
+	if t == nil {
+		t = cBackend{}
+	}
+	tableMu.Lock()
+	old := table
+	table = t
+	tableMu.Unlock()
+	flushDisplayCache()
+	return old
+}
+
+func be() funcTable {
+	tableMu.RLock()
+	defer tableMu.RUnlock()
+	return table
+}
+
+// isLibEGL tells whether the EGL calls go to libEGL.
+func isLibEGL() bool {
+	_, ok := be().(cBackend)
+	return ok
+}
+
+func Initialize(d Display) bool {
+	major, minor, ok := be().Initialize(d)
+	if ok {
+		Version.Maj, Version.Min = major, minor
+		setDisplayVersion(d, int(major), int(minor))
+	}
+	return ok
+}
+func Terminate(d Display) bool {
+	return be().Terminate(d)
+}
+func GetDisplay(d NativeDisplay) Display {
+	return be().GetDisplay(d)
+}
+func QueryString(d Display, name int) string {
+	s, _ := be().QueryString(d, name)
+	return s
+}
+func DestroySurface(d Display, s Surface) bool {
+	return be().DestroySurface(d, s)
+}
+func SwapInterval(d Display, inv int) bool {
+	return be().SwapInterval(d, inv)
+}
+func DestroyContext(d Display, c Context) bool {
+	return be().DestroyContext(d, c)
+}
+func GetCurrentSurface(readdraw int) Surface {
+	return be().GetCurrentSurface(readdraw)
+}
+func QuerySurface(d Display, s Surface, attr int) (EGLint, bool) {
+	return be().QuerySurface(d, s, attr)
+}
+func GetConfigs(d Display, confs []Config) int {
+	if n, ok := be().GetConfigs(d, confs); ok {
+		return n
+	}
+	return 0
+}
+
+func GetConfigAttrib(d Display, conf Config, attr int) (int, bool) {
+	return be().GetConfigAttrib(d, conf, attr)
+}
+func ChooseConfig(d Display, atrribs []EGLint, confs []Config) int {
+	if n, ok := be().ChooseConfig(d, atrribs, confs); ok {
+		return n
+	}
+	return 0
+}
+
+func CreateContext(d Display, conf Config, shared Context, attribs []EGLint) Context {
+	return be().CreateContext(d, conf, shared, attribs)
+}
+
+func CreateWindowSurface(d Display, conf Config, win NativeWindow, attribs []EGLint) Surface {
+	return be().CreateWindowSurface(d, conf, win, attribs)
+}
+func CreatePbufferSurface(d Display, conf Config, attribs []EGLint) Surface {
+	return be().CreatePbufferSurface(d, conf, attribs)
+}
+func CreatePixmapSurface(d Display, conf Config, pixmap NativePixmap, attribs []EGLint) Surface {
+	return be().CreatePixmapSurface(d, conf, pixmap, attribs)
+}
+func CreatePbufferFromClientBuffer(
+	d Display, buftyp uint, conf Config, buf ClientBuffer, attribs []EGLint) Surface {
+	return be().CreatePbufferFromClientBuffer(d, buftyp, conf, buf, attribs)
+}
+func SurfaceAttrib(d Display, s Surface, attr int, val int) bool {
+	return be().SurfaceAttrib(d, s, attr, val)
+}
+func BindTexImage(d Display, s Surface, buf int) bool {
+	return be().BindTexImage(d, s, buf)
+}
+func ReleaseTexImage(d Display, s Surface, buf int) bool {
+	return be().ReleaseTexImage(d, s, buf)
+}
+func MakeCurrent(d Display, draw Surface, read Surface, c Context) bool {
+	return be().MakeCurrent(d, draw, read, c)
+}
+func QueryContext(d Display, c Context, attr int, val []EGLint) bool {
+	v, ok := be().QueryContext(d, c, attr)
+	if ok {
+		val[0] = v
+	}
+	return ok
+}
+func CopyBuffers(d Display, s Surface, target NativePixmap) bool {
+	return be().CopyBuffers(d, s, target)
+}
+func SwapBuffers(d Display, s Surface) bool {
+	return be().SwapBuffers(d, s)
+}
+
+func BindAPI(api uint) bool      { return be().BindAPI(api) }
+func WaitNative(engine int) bool { return be().WaitNative(engine) }
+func QueryAPI() uint             { return be().QueryAPI() }
+func WaitClient() bool           { return be().WaitClient() }
+func WaitGL() bool               { return be().WaitGL() }
+func ReleaseThread() bool        { return be().ReleaseThread() }
+func GetCurrentDisplay() Display { return be().GetCurrentDisplay() }
+func GetError() Error            { return be().GetError() }
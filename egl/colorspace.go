@@ -0,0 +1,141 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Colorspace is the colorspace GL renders into on a surface, the value of
+// the GL_COLORSPACE_KHR surface attribute.
+type Colorspace int
+
+// EGL 1.5 / EGL_KHR_gl_colorspace
+const (
+	GL_COLORSPACE_KHR = 0x309D
+	GL_COLORSPACE     = GL_COLORSPACE_KHR
+
+	GL_COLORSPACE_SRGB_KHR   Colorspace = 0x3089
+	GL_COLORSPACE_LINEAR_KHR Colorspace = 0x308A
+	GL_COLORSPACE_SRGB       Colorspace = GL_COLORSPACE_SRGB_KHR
+	GL_COLORSPACE_LINEAR     Colorspace = GL_COLORSPACE_LINEAR_KHR
+)
+
+// EGL_EXT_gl_colorspace_*
+const (
+	GL_COLORSPACE_DISPLAY_P3_EXT             Colorspace = 0x3363
+	GL_COLORSPACE_DISPLAY_P3_LINEAR_EXT      Colorspace = 0x3362
+	GL_COLORSPACE_DISPLAY_P3_PASSTHROUGH_EXT Colorspace = 0x3490
+	GL_COLORSPACE_SCRGB_EXT                  Colorspace = 0x3351
+	GL_COLORSPACE_SCRGB_LINEAR_EXT           Colorspace = 0x3350
+	GL_COLORSPACE_BT2020_PQ_EXT              Colorspace = 0x3340
+	GL_COLORSPACE_BT2020_LINEAR_EXT          Colorspace = 0x333F
+)
+
+var colorspaceExtensions = map[Colorspace]Extension{
+	GL_COLORSPACE_SRGB:                       KHR_gl_colorspace,
+	GL_COLORSPACE_LINEAR:                     KHR_gl_colorspace,
+	GL_COLORSPACE_DISPLAY_P3_EXT:             EXT_gl_colorspace_display_p3,
+	GL_COLORSPACE_DISPLAY_P3_LINEAR_EXT:      EXT_gl_colorspace_display_p3_linear,
+	GL_COLORSPACE_DISPLAY_P3_PASSTHROUGH_EXT: EXT_gl_colorspace_display_p3_passthrough,
+	GL_COLORSPACE_SCRGB_EXT:                  EXT_gl_colorspace_scrgb,
+	GL_COLORSPACE_SCRGB_LINEAR_EXT:           EXT_gl_colorspace_scrgb_linear,
+	GL_COLORSPACE_BT2020_PQ_EXT:              EXT_gl_colorspace_bt2020_pq,
+	GL_COLORSPACE_BT2020_LINEAR_EXT:          EXT_gl_colorspace_bt2020_linear,
+}
+
+func (cs Colorspace) String() string {
+	switch cs {
+	case GL_COLORSPACE_SRGB:
+		return "sRGB"
+	case GL_COLORSPACE_LINEAR:
+		return "linear"
+	case GL_COLORSPACE_DISPLAY_P3_EXT:
+		return "Display-P3"
+	case GL_COLORSPACE_DISPLAY_P3_LINEAR_EXT:
+		return "Display-P3 linear"
+	case GL_COLORSPACE_DISPLAY_P3_PASSTHROUGH_EXT:
+		return "Display-P3 passthrough"
+	case GL_COLORSPACE_SCRGB_EXT:
+		return "scRGB"
+	case GL_COLORSPACE_SCRGB_LINEAR_EXT:
+		return "scRGB linear"
+	case GL_COLORSPACE_BT2020_PQ_EXT:
+		return "BT.2020 PQ"
+	case GL_COLORSPACE_BT2020_LINEAR_EXT:
+		return "BT.2020 linear"
+	}
+	return fmt.Sprintf("colorspace 0x%x", int(cs))
+}
+
+// SupportsColorspace reports whether surfaces of display d can use cs.
+// sRGB and linear need EGL 1.5 or EGL_KHR_gl_colorspace, the wide gamut
+// colorspaces their EGL_EXT_gl_colorspace_* extension. d must be
+// initialized.
+func SupportsColorspace(d Display, cs Colorspace) bool {
+	ext, ok := colorspaceExtensions[cs]
+	if !ok {
+		return false
+	}
+	if ext == KHR_gl_colorspace && isCore15(d) {
+		return true
+	}
+	return DisplayExtensions(d).Has(ext)
+}
+
+// SetColorspace sets the colorspace of the surfaces created afterwards by
+// InitEGLSurface, Resume, AttachWindow and AttachPbuffer, 0 for the
+// default (linear). A colorspace the display or config does not support
+// is left out; Colorspace tells what was applied.
+func (ctx *EGLContext) SetColorspace(cs Colorspace) {
+	ctx.colorspace = cs
+}
+
+// Colorspace queries the colorspace applied to the surface.
+func (ctx *EGLContext) Colorspace() Colorspace {
+	return surfaceColorspace(ctx.display, ctx.surface)
+}
+
+// Colorspace queries the colorspace applied to the drawable.
+func (d *Drawable) Colorspace() Colorspace {
+	return surfaceColorspace(d.ctx.display, d.surface)
+}
+
+func surfaceColorspace(d Display, s Surface) Colorspace {
+	if s == NO_SURFACE {
+		return 0
+	}
+	if cs, ok := QuerySurface(d, s, GL_COLORSPACE); ok {
+		return Colorspace(cs)
+	}
+	// without colorspace support, everything is linear
+	return GL_COLORSPACE_LINEAR
+}
+
+// surfaceAttribs appends the colorspace and alpha format to attribs, if
+// supported, and terminates the list.
+func (ctx *EGLContext) surfaceAttribs(attribs ...EGLint) []EGLint {
+	attribs = append(attribs, ctx.alphaAttribs()...)
+	if ctx.colorspace != 0 {
+		if SupportsColorspace(ctx.display, ctx.colorspace) {
+			attribs = append(attribs, GL_COLORSPACE, EGLint(ctx.colorspace))
+		} else {
+			ctx.log(slog.LevelWarn, "surface", "EGL colorspace unsupported: "+ctx.colorspace.String(), nil)
+		}
+	}
+	return append(attribs, NONE)
+}
+
+// createSurfaceWith calls create with the attributes of surfaceAttribs
+// and, if the config cannot be used with them, again without.
+func (ctx *EGLContext) createSurfaceWith(create func([]EGLint) (Surface, error), attribs ...EGLint) (Surface, error) {
+	s, err := create(ctx.surfaceAttribs(attribs...))
+	if err != nil && (ctx.colorspace != 0 || ctx.translucent) && ErrorCode(err) == BAD_MATCH {
+		ctx.log(slog.LevelWarn, "surface", "EGL colorspace or alpha format refused for config", err)
+		s, err = create(append(attribs, NONE))
+	}
+	return s, err
+}
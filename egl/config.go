@@ -0,0 +1,190 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConfigField names the ConfigSpec fields that can be marked as required.
+type ConfigField uint
+
+const (
+	ConfigColor ConfigField = 1 << iota // RedSize, GreenSize, BlueSize
+	ConfigAlpha
+	ConfigDepth
+	ConfigStencil
+	ConfigSamples
+)
+
+// CaveatPolicy says how configs with a CONFIG_CAVEAT are treated.
+type CaveatPolicy int
+
+const (
+	CaveatAvoid  CaveatPolicy = iota // accept, but rank below caveat-free configs
+	CaveatAccept                     // ignore the caveat
+	CaveatReject                     // reject slow and non-conformant configs
+)
+
+// ConfigSpec describes the framebuffer configuration wanted from a display.
+//
+// SurfaceType and RenderableType are bitmasks (WINDOW_BIT, OPENGL_ES2_BIT,
+// ...) and are always required. The size fields are preferences unless
+// their ConfigField is set in Required, in which case a config with a
+// smaller value is rejected. Among the remaining configs the one closest
+// to the spec wins; missing bits cost four times as much as extra ones.
+type ConfigSpec struct {
+	RedSize, GreenSize, BlueSize int
+	AlphaSize                    int
+	DepthSize                    int
+	StencilSize                  int
+	Samples                      int
+	SurfaceType                  int
+	RenderableType               int
+	Caveat                       CaveatPolicy
+	Required                     ConfigField
+	// Translucent prefers configs with a TRANSPARENT_RGB transparent
+	// type and the VG_ALPHA_FORMAT_PRE_BIT surface type, for surfaces
+	// composited over other content.
+	Translucent bool
+}
+
+// ConfigCandidate is one config of a display as ranked against a spec.
+type ConfigCandidate struct {
+	Config Config
+	ID     int
+	Score  int    // lower is better
+	Reject string // why the config was rejected, empty if accepted
+}
+
+// NoConfigError is returned by ChooseConfigSpec when no config of the
+// display satisfies the spec.
+type NoConfigError struct {
+	Spec     ConfigSpec
+	Rejected []ConfigCandidate
+}
+
+func (e *NoConfigError) Error() string {
+	if len(e.Rejected) == 0 {
+		return "egl: display has no configs"
+	}
+	reasons := make([]string, len(e.Rejected))
+	for i, c := range e.Rejected {
+		reasons[i] = fmt.Sprintf("config %d: %s", c.ID, c.Reject)
+	}
+	return "egl: no config matches spec: " + strings.Join(reasons, "; ")
+}
+
+// Configs returns every config the display exposes.
+func Configs(d Display) ([]Config, error) {
+	n, err := GetConfigsErr(d, nil)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	confs := make([]Config, n)
+	n, err = GetConfigsErr(d, confs)
+	return confs[:n], err
+}
+
+// RankConfigs scores every config of the display against spec. Accepted
+// configs come first, best first, followed by the rejected ones.
+func RankConfigs(d Display, spec ConfigSpec) ([]ConfigCandidate, error) {
+	confs, err := Configs(d)
+	if err != nil {
+		return nil, err
+	}
+	cands := make([]ConfigCandidate, len(confs))
+	for i, conf := range confs {
+		cands[i] = spec.rank(d, conf)
+	}
+	sort.SliceStable(cands, func(i, j int) bool {
+		ri, rj := cands[i].Reject != "", cands[j].Reject != ""
+		if ri != rj {
+			return rj
+		}
+		return cands[i].Score < cands[j].Score
+	})
+	return cands, nil
+}
+
+// ChooseConfigSpec returns the best config of the display for spec.
+func ChooseConfigSpec(d Display, spec ConfigSpec) (Config, error) {
+	cands, err := RankConfigs(d, spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(cands) == 0 || cands[0].Reject != "" {
+		return nil, &NoConfigError{Spec: spec, Rejected: cands}
+	}
+	return cands[0].Config, nil
+}
+
+func (spec ConfigSpec) rank(d Display, conf Config) ConfigCandidate {
+	info, err := GetConfigInfo(d, conf)
+	if err != nil {
+		// a config that cannot be queried would be scored as all zeros
+		return ConfigCandidate{Config: conf, ID: info.ID, Reject: err.Error()}
+	}
+	return spec.Rank(conf, info)
+}
+
+// Rank scores a config, described by info, against spec.
+func (spec ConfigSpec) Rank(conf Config, info ConfigInfo) ConfigCandidate {
+	c := ConfigCandidate{Config: conf, ID: info.ID}
+
+	if st := info.SurfaceType; st&spec.SurfaceType != spec.SurfaceType {
+		c.Reject = fmt.Sprintf("surface type 0x%x lacks 0x%x", st, spec.SurfaceType)
+		return c
+	}
+	if rt := info.RenderableType; rt&spec.RenderableType != spec.RenderableType {
+		c.Reject = fmt.Sprintf("renderable type 0x%x lacks 0x%x", rt, spec.RenderableType)
+		return c
+	}
+	switch caveat := info.Caveat; {
+	case caveat == NONE || spec.Caveat == CaveatAccept:
+	case spec.Caveat == CaveatReject:
+		c.Reject = fmt.Sprintf("caveat 0x%x", caveat)
+		return c
+	default:
+		c.Score += 1000
+	}
+
+	fields := []struct {
+		field      ConfigField
+		name       string
+		have, want int
+		weight     int
+	}{
+		{ConfigColor, "red size", info.RedSize, spec.RedSize, 2},
+		{ConfigColor, "green size", info.GreenSize, spec.GreenSize, 2},
+		{ConfigColor, "blue size", info.BlueSize, spec.BlueSize, 2},
+		{ConfigAlpha, "alpha size", info.AlphaSize, spec.AlphaSize, 1},
+		{ConfigDepth, "depth size", info.DepthSize, spec.DepthSize, 1},
+		{ConfigStencil, "stencil size", info.StencilSize, spec.StencilSize, 1},
+		{ConfigSamples, "samples", info.Samples, spec.Samples, 1},
+	}
+	for _, f := range fields {
+		if f.have < f.want {
+			if spec.Required&f.field != 0 {
+				c.Reject = fmt.Sprintf("%s %d < %d", f.name, f.have, f.want)
+				return c
+			}
+			c.Score += 4 * f.weight * (f.want - f.have)
+		} else {
+			c.Score += f.weight * (f.have - f.want)
+		}
+	}
+	if spec.Translucent {
+		if info.TransparentType != TRANSPARENT_RGB {
+			c.Score++
+		}
+		if info.SurfaceType&VG_ALPHA_FORMAT_PRE_BIT == 0 {
+			c.Score++
+		}
+	}
+	return c
+}
@@ -0,0 +1,166 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigInfo holds every attribute of an EGLConfig.
+type ConfigInfo struct {
+	ID                int
+	BufferSize        int
+	RedSize           int
+	GreenSize         int
+	BlueSize          int
+	LuminanceSize     int
+	AlphaSize         int
+	AlphaMaskSize     int
+	DepthSize         int
+	StencilSize       int
+	Samples           int
+	SampleBuffers     int
+	ColorBufferType   int // RGB_BUFFER or LUMINANCE_BUFFER
+	Caveat            int // NONE, SLOW_CONFIG or NON_CONFORMANT_CONFIG
+	Conformant        int // RENDERABLE_TYPE bits
+	RenderableType    int // RENDERABLE_TYPE bits
+	SurfaceType       int // SURFACE_TYPE bits
+	Level             int
+	MaxPbufferWidth   int
+	MaxPbufferHeight  int
+	MaxPbufferPixels  int
+	MinSwapInterval   int
+	MaxSwapInterval   int
+	NativeRenderable  bool
+	NativeVisualID    int
+	NativeVisualType  int
+	TransparentType   int // NONE or TRANSPARENT_RGB
+	TransparentRed    int
+	TransparentGreen  int
+	TransparentBlue   int
+	BindToTextureRGB  bool
+	BindToTextureRGBA bool
+}
+
+// GetConfigInfo reads all attributes of conf.
+func GetConfigInfo(d Display, conf Config) (ConfigInfo, error) {
+	var info ConfigInfo
+	var err error
+	attr := func(a int) int {
+		v, e := GetConfigAttribErr(d, conf, a)
+		if err == nil {
+			err = e
+		}
+		return v
+	}
+	info.ID = attr(CONFIG_ID)
+	info.BufferSize = attr(BUFFER_SIZE)
+	info.RedSize = attr(RED_SIZE)
+	info.GreenSize = attr(GREEN_SIZE)
+	info.BlueSize = attr(BLUE_SIZE)
+	info.LuminanceSize = attr(LUMINANCE_SIZE)
+	info.AlphaSize = attr(ALPHA_SIZE)
+	info.AlphaMaskSize = attr(ALPHA_MASK_SIZE)
+	info.DepthSize = attr(DEPTH_SIZE)
+	info.StencilSize = attr(STENCIL_SIZE)
+	info.Samples = attr(SAMPLES)
+	info.SampleBuffers = attr(SAMPLE_BUFFERS)
+	info.ColorBufferType = attr(COLOR_BUFFER_TYPE)
+	info.Caveat = attr(CONFIG_CAVEAT)
+	info.Conformant = attr(CONFORMANT)
+	info.RenderableType = attr(RENDERABLE_TYPE)
+	info.SurfaceType = attr(SURFACE_TYPE)
+	info.Level = attr(LEVEL)
+	info.MaxPbufferWidth = attr(MAX_PBUFFER_WIDTH)
+	info.MaxPbufferHeight = attr(MAX_PBUFFER_HEIGHT)
+	info.MaxPbufferPixels = attr(MAX_PBUFFER_PIXELS)
+	info.MinSwapInterval = attr(MIN_SWAP_INTERVAL)
+	info.MaxSwapInterval = attr(MAX_SWAP_INTERVAL)
+	info.NativeRenderable = attr(NATIVE_RENDERABLE) != 0
+	info.NativeVisualID = attr(NATIVE_VISUAL_ID)
+	info.NativeVisualType = attr(NATIVE_VISUAL_TYPE)
+	info.TransparentType = attr(TRANSPARENT_TYPE)
+	info.TransparentRed = attr(TRANSPARENT_RED_VALUE)
+	info.TransparentGreen = attr(TRANSPARENT_GREEN_VALUE)
+	info.TransparentBlue = attr(TRANSPARENT_BLUE_VALUE)
+	info.BindToTextureRGB = attr(BIND_TO_TEXTURE_RGB) != 0
+	info.BindToTextureRGBA = attr(BIND_TO_TEXTURE_RGBA) != 0
+	return info, err
+}
+
+func (info ConfigInfo) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "config %d:", info.ID)
+	if info.ColorBufferType == LUMINANCE_BUFFER {
+		fmt.Fprintf(&b, " L%d", info.LuminanceSize)
+	} else {
+		fmt.Fprintf(&b, " R%dG%dB%d", info.RedSize, info.GreenSize, info.BlueSize)
+	}
+	fmt.Fprintf(&b, "A%d buffer %d depth %d stencil %d",
+		info.AlphaSize, info.BufferSize, info.DepthSize, info.StencilSize)
+	if info.SampleBuffers > 0 {
+		fmt.Fprintf(&b, " samples %d", info.Samples)
+	}
+	fmt.Fprintf(&b, " surface %s renderable %s conformant %s",
+		bitNames(info.SurfaceType, surfaceTypeNames),
+		bitNames(info.RenderableType, renderableTypeNames),
+		bitNames(info.Conformant, renderableTypeNames))
+	switch info.Caveat {
+	case SLOW_CONFIG:
+		b.WriteString(" caveat slow")
+	case NON_CONFORMANT_CONFIG:
+		b.WriteString(" caveat non-conformant")
+	}
+	fmt.Fprintf(&b, " visual 0x%x", info.NativeVisualID)
+	if info.NativeRenderable {
+		b.WriteString(" native-renderable")
+	}
+	if info.TransparentType == TRANSPARENT_RGB {
+		fmt.Fprintf(&b, " transparent (%d,%d,%d)",
+			info.TransparentRed, info.TransparentGreen, info.TransparentBlue)
+	}
+	if info.BindToTextureRGB {
+		b.WriteString(" bind-rgb")
+	}
+	if info.BindToTextureRGBA {
+		b.WriteString(" bind-rgba")
+	}
+	fmt.Fprintf(&b, " swap %d..%d", info.MinSwapInterval, info.MaxSwapInterval)
+	if info.MaxPbufferWidth > 0 {
+		fmt.Fprintf(&b, " pbuffer %dx%d", info.MaxPbufferWidth, info.MaxPbufferHeight)
+	}
+	return b.String()
+}
+
+var surfaceTypeNames = []string{
+	"pbuffer", "pixmap", "window", "", "",
+	"vg-colorspace-linear", "vg-alpha-format-pre", "", "",
+	"multisample-resolve-box", "swap-behavior-preserved",
+}
+
+var renderableTypeNames = []string{
+	"es", "vg", "es2", "gl", "", "", "es3",
+}
+
+// bitNames formats a bitmask as names joined by '|', falling back to
+// hex for bits without a name.
+func bitNames(mask int, names []string) string {
+	if mask == 0 {
+		return "none"
+	}
+	var parts []string
+	for i := uint(0); mask>>i != 0; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		if int(i) < len(names) && names[i] != "" {
+			parts = append(parts, names[i])
+		} else {
+			parts = append(parts, fmt.Sprintf("0x%x", 1<<i))
+		}
+	}
+	return strings.Join(parts, "|")
+}
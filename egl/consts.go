@@ -10,6 +10,8 @@ package egl
 */
 import "C"
 
+import "fmt"
+
 /* EGL Versioning */
 const (
 	VERSION_1_0 = 1
@@ -19,6 +21,12 @@ const (
 	VERSION_1_4 = 1
 )
 
+/* EGL aliases */
+const (
+	FALSE = 0
+	TRUE  = 1
+)
+
 // Out-of-band attribute value
 const (
 	DONT_CARE = -1
@@ -113,8 +121,10 @@ func (e Error) Error() string {
 		return pre + "bad surface"
 	case CONTEXT_LOST:
 		return pre + "context lost"
+	case BAD_DEVICE_EXT:
+		return pre + "bad device"
 	default:
-		return ""
+		return fmt.Sprintf("%s0x%x", pre, int(e))
 	}
 }
 
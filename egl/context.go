@@ -5,7 +5,8 @@
 package egl
 
 import (
-	"log"
+	"errors"
+	"log/slog"
 	"unsafe"
 )
 
@@ -15,15 +16,34 @@ type EGLContext struct {
 	display       Display
 	context       Context
 	config        Config
+	info          ConfigInfo // of config, for logging
 	window        NativeWindow
 	esMajor       EGLint
 	esMinor       EGLint
 	depthSize     EGLint
-	bAntialiasing bool
+	stencilSize   EGLint
+	samples       EGLint
+	spec          ConfigSpec
+	err           error
+	pbufferWidth  EGLint
+	pbufferHeight EGLint
+	surfaceless   bool
+	share         *EGLContext
+	observer      Observer
+	width         int
+	height        int
+	drawables     []*Drawable
+	draw, read    *Drawable
+	openDisplay   func() (Display, error)
+	opts          ContextOptions
+	obtained      ContextOptions
+	colorspace    Colorspace
+	translucent   bool
 }
 
 /* depthSize : 16, 24
  * esVersion : 2 (es2), 3 (es3)
+ * NewContext and NewContextEx prefer 4x multisampling without stencil.
  */
 func NewContext(window NativeWindow, ndisplay NativeDisplay) *EGLContext {
 	return NewContextEx(window, ndisplay, 16, 2, 0)
@@ -38,16 +58,207 @@ func NewContextEx(window NativeWindow, ndisplay NativeDisplay, depthSize, esVers
 	}
 	//display := Display(nil)
 	display := GetDisplay(ndisplay)
+	ctx := &EGLContext{window: window,
+		openDisplay: func() (Display, error) {
+			return GetDisplayErr(ndisplay)
+		},
+		display:   display,
+		surface:   NO_SURFACE,
+		depthSize: EGLint(depthSize),
+		esMajor:   EGLint(esVersion),
+		esMinor:   EGLint(minor),
+		samples:   4,
+		opts:      ContextOptions{Major: esVersion, Minor: minor}}
 	if display == nil {
-		log.Println("EGL GetDisplay failed")
+		ctx.log(slog.LevelError, "display", "EGL GetDisplay failed", nil)
 	}
-	return &EGLContext{window: window,
-		display:       display,
-		surface:       NO_SURFACE,
-		depthSize:     EGLint(depthSize),
-		esMajor:       EGLint(esVersion),
-		esMinor:       EGLint(minor),
-		bAntialiasing: true}
+	ctx.spec = ctx.defaultSpec()
+	return ctx
+}
+
+// NewContextMultisample is NewContextEx with an explicit stencil size and
+// sample count, 0 for none. The config with the closest buffers is chosen
+// when no config has them all: with fewer samples down to single
+// sampling, with a smaller or no stencil buffer. ConfigInfo tells what was
+// obtained; set ConfigSpec.Required to fail instead.
+func NewContextMultisample(window NativeWindow, ndisplay NativeDisplay, depthSize, stencilSize, samples, esVersion, minor int) *EGLContext {
+	ctx := NewContextEx(window, ndisplay, depthSize, esVersion, minor)
+	ctx.stencilSize = EGLint(stencilSize)
+	ctx.samples = EGLint(samples)
+	ctx.spec = ctx.defaultSpec()
+	return ctx
+}
+
+// SetPlatformDisplay makes the context use the display of native on an
+// explicit platform, e.g. PLATFORM_SURFACELESS_MESA for headless Mesa,
+// instead of the default display. It must be called before
+// InitEGLSurface.
+func (ctx *EGLContext) SetPlatformDisplay(platform Platform, native unsafe.Pointer, attribs []EGLAttrib) bool {
+	open := func() (Display, error) {
+		return GetPlatformDisplayErr(platform, native, attribs)
+	}
+	display, err := open()
+	if err != nil {
+		ctx.err = err
+		return false
+	}
+	ctx.display = display
+	ctx.openDisplay = open
+	return true
+}
+
+// NewPbufferContext returns an EGLContext that renders into an offscreen
+// pbuffer of width x height instead of a window, e.g. for headless
+// rendering on Mesa's software rasterizer. Build the es2 package with the
+// egl tag so that it loads its entry points through EGL.
+// Like NewContext it prefers 4x multisampling without stencil.
+func NewPbufferContext(ndisplay NativeDisplay, width, height, depthSize, esVersion, minor int) *EGLContext {
+	return NewPbufferContextMultisample(ndisplay, width, height, depthSize, 0, 4, esVersion, minor)
+}
+
+// NewPbufferContextMultisample is NewPbufferContext with an explicit
+// stencil size and sample count, 0 for none, with the fallbacks of
+// NewContextMultisample.
+func NewPbufferContextMultisample(ndisplay NativeDisplay, width, height, depthSize, stencilSize, samples, esVersion, minor int) *EGLContext {
+	ctx := NewContextMultisample(nil, ndisplay, depthSize, stencilSize, samples, esVersion, minor)
+	ctx.pbufferWidth = EGLint(width)
+	ctx.pbufferHeight = EGLint(height)
+	ctx.spec.SurfaceType = PBUFFER_BIT
+	return ctx
+}
+
+// ErrNoSurfaceless is reported by InitEGLSurface of a surfaceless context
+// when the display lacks EGL_KHR_surfaceless_context.
+var ErrNoSurfaceless = errors.New("egl: display does not support EGL_KHR_surfaceless_context")
+
+// NewSurfacelessContext returns an EGLContext without any surface. It is
+// made current with NO_SURFACE, so all rendering must go to framebuffer
+// objects. The display must advertise EGL_KHR_surfaceless_context.
+// Without a default framebuffer no depth, stencil or multisample buffers
+// are asked for; attach them to the framebuffer objects instead.
+func NewSurfacelessContext(ndisplay NativeDisplay, esVersion, minor int) *EGLContext {
+	ctx := NewContextMultisample(nil, ndisplay, 16, 0, 0, esVersion, minor)
+	ctx.surfaceless = true
+	ctx.spec.SurfaceType = 0
+	ctx.spec.DepthSize = 0
+	return ctx
+}
+
+// NewSharedContext returns an EGLContext on the display of primary that
+// shares textures, buffers and other objects with it. The new context is
+// surfaceless if the display supports it and uses a 1x1 pbuffer
+// otherwise. primary must have been initialized, and must outlive the
+// shared context: terminating the shared context leaves the display
+// alone.
+func NewSharedContext(primary *EGLContext) *EGLContext {
+	ctx := &EGLContext{
+		display:   primary.display,
+		surface:   NO_SURFACE,
+		depthSize: primary.depthSize,
+		esMajor:   primary.esMajor,
+		esMinor:   primary.esMinor,
+		spec:      primary.spec,
+		share:     primary,
+		opts:      primary.obtained,
+	}
+	ctx.openDisplay = func() (Display, error) {
+		return primary.display, nil
+	}
+	ctx.spec.DepthSize = 0
+	ctx.spec.StencilSize = 0
+	ctx.spec.Samples = 0
+	if primary.Extensions().Has(KHR_surfaceless_context) {
+		ctx.surfaceless = true
+		ctx.spec.SurfaceType = 0
+	} else {
+		ctx.pbufferWidth, ctx.pbufferHeight = 1, 1
+		ctx.spec.SurfaceType = PBUFFER_BIT
+	}
+	return ctx
+}
+
+// IsSurfaceless reports whether the context is used without a surface.
+func (ctx *EGLContext) IsSurfaceless() bool {
+	return ctx.surfaceless
+}
+
+// IsPbuffer reports whether the context renders into a pbuffer.
+func (ctx *EGLContext) IsPbuffer() bool {
+	return ctx.window == nil && ctx.pbufferWidth > 0
+}
+
+// createSurface creates the window or pbuffer surface for ctx.config.
+// Surfaceless contexts get NO_SURFACE.
+func (ctx *EGLContext) createSurface() (Surface, error) {
+	if ctx.surfaceless {
+		return NO_SURFACE, nil
+	}
+	if ctx.IsPbuffer() {
+		return ctx.createPbuffer(ctx.pbufferWidth, ctx.pbufferHeight)
+	}
+	return ctx.createWindowSurface(ctx.window)
+}
+
+func (ctx *EGLContext) createPbuffer(width, height EGLint) (Surface, error) {
+	return ctx.createSurfaceWith(func(attribs []EGLint) (Surface, error) {
+		return CreatePbufferSurfaceErr(ctx.display, ctx.config, attribs)
+	}, WIDTH, width, HEIGHT, height)
+}
+
+func (ctx *EGLContext) createWindowSurface(win NativeWindow) (Surface, error) {
+	return ctx.createSurfaceWith(func(attribs []EGLint) (Surface, error) {
+		return CreateWindowSurfaceErr(ctx.display, ctx.config, win, attribs)
+	})
+}
+
+// defaultSpec is the config wanted by the constructors: 8/8/8 color for
+// depth sizes above 16 and 5/6/5 otherwise, with the stencil size and
+// sample count asked for if possible.
+func (ctx *EGLContext) defaultSpec() ConfigSpec {
+	spec := ConfigSpec{
+		RedSize: 5, GreenSize: 6, BlueSize: 5,
+		DepthSize:      int(ctx.depthSize),
+		SurfaceType:    WINDOW_BIT,
+		RenderableType: OPENGL_ES2_BIT,
+	}
+	if ctx.depthSize > 16 {
+		spec.RedSize, spec.GreenSize, spec.BlueSize = 8, 8, 8
+	}
+	if ctx.esMajor == 3 {
+		spec.RenderableType = OPENGL_ES3_BIT
+	}
+	spec.StencilSize = int(ctx.stencilSize)
+	spec.Samples = int(ctx.samples)
+	return spec
+}
+
+// ConfigSpec returns the spec InitEGLSurface chooses the config with,
+// without the alpha asked for by SetTranslucent.
+func (ctx *EGLContext) ConfigSpec() ConfigSpec {
+	return ctx.spec
+}
+
+// SetConfigSpec replaces the spec used to choose the config. It takes
+// effect on the next InitEGLSurface.
+func (ctx *EGLContext) SetConfigSpec(spec ConfigSpec) {
+	ctx.spec = spec
+}
+
+// Err returns the reason of the last failed InitEGLSurface or
+// InitEGLContext.
+func (ctx *EGLContext) Err() error {
+	return ctx.err
+}
+
+// Display returns the EGL display of the context.
+func (ctx *EGLContext) Display() Display {
+	return ctx.display
+}
+
+// Context returns the EGL context handle, NO_CONTEXT before
+// InitEGLContext.
+func (ctx *EGLContext) Context() Context {
+	return ctx.context
 }
 
 func (ctx *EGLContext) GetFormat() int {
@@ -55,9 +266,14 @@ func (ctx *EGLContext) GetFormat() int {
 	return format
 }
 
+// ConfigInfo describes the config chosen by InitEGLSurface.
+func (ctx *EGLContext) ConfigInfo() (ConfigInfo, error) {
+	return GetConfigInfo(ctx.display, ctx.config)
+}
+
 func (ctx *EGLContext) InitEGLSurfaceX() bool {
 	if !Initialize(ctx.display) {
-		log.Println("EGL initialize failed")
+		ctx.log(slog.LevelError, "initialize", "EGL initialize failed", nil)
 		return false
 	}
 
@@ -111,79 +327,44 @@ func (ctx *EGLContext) InitEGLSurfaceX() bool {
 }
 
 func (ctx *EGLContext) InitEGLSurface() bool {
-	if !Initialize(ctx.display) {
-		log.Println("EGL initialize failed")
+	if err := InitializeErr(ctx.display); err != nil {
+		ctx.log(slog.LevelError, "initialize", "EGL initialize failed", err)
+		ctx.err = err
 		return false
 	}
 
-	var depths []int
-	if ctx.depthSize > 16 {
-		depths = []int{32, 16}
-	} else {
-		depths = []int{16, 32}
-	}
-
-	for _, depth := range depths {
-		var attribs []EGLint
-		if depth > 16 {
-			attribs = []EGLint{RENDERABLE_TYPE, OPENGL_ES2_BIT, //Request opengl ES2.0
-				BLUE_SIZE, 8, GREEN_SIZE, 8, RED_SIZE, 8,
-				NONE}
-		} else {
-			attribs = []EGLint{RENDERABLE_TYPE, OPENGL_ES2_BIT, //Request opengl ES2.0
-				BLUE_SIZE, 5, GREEN_SIZE, 6, RED_SIZE, 5,
-				NONE}
-		}
-
-		var confs [16]Config
-		if ctx.esMajor == 3 {
-			attribs[1] = OPENGL_ES3_BIT
-		}
-		num_configs := ChooseConfig(ctx.display, attribs, confs[:])
-		if 0 == num_configs {
-			continue
-		}
-
-		// find match config
-		for _, conf := range confs[:num_configs] {
-			r, _ := GetConfigAttrib(ctx.display, conf, RED_SIZE)
-			g, _ := GetConfigAttrib(ctx.display, conf, GREEN_SIZE)
-			b, _ := GetConfigAttrib(ctx.display, conf, BLUE_SIZE)
-			s, _ := GetConfigAttrib(ctx.display, conf, SAMPLE_BUFFERS)
-			if (ctx.depthSize > 16 && r == 8 && g == 8 && b == 8) ||
-				(ctx.depthSize == 16 && r == 5 && g == 6 && b == 5) {
-				ctx.config = conf
-				if ctx.bAntialiasing {
-					if s > 0 {
-						break
-					}
-				} else {
-					break
-				}
-			}
-		}
-
-		if ctx.config == nil {
-			ctx.config = confs[0]
-		}
+	if ctx.surfaceless && !ctx.Extensions().Has(KHR_surfaceless_context) {
+		ctx.log(slog.LevelError, "surface", "EGL surfaceless context unsupported", ErrNoSurfaceless)
+		ctx.err = ErrNoSurfaceless
+		return false
 	}
 
-	if ctx.config == nil {
+	conf, err := ChooseConfigSpec(ctx.display, ctx.chooseSpec())
+	if err != nil {
+		ctx.log(slog.LevelError, "config", "EGL choose config failed", err)
+		ctx.err = err
 		return false
 	}
+	ctx.config = conf
+	ctx.info, _ = GetConfigInfo(ctx.display, conf)
 
-	ctx.surface = CreateWindowSurface(ctx.display, ctx.config, ctx.window, nil)
-	if ctx.surface == nil {
+	ctx.surface, err = ctx.createSurface()
+	if err != nil {
+		ctx.log(slog.LevelError, "surface", "EGL create surface failed", err)
+		ctx.err = err
 		return false
 	}
 
+	ctx.err = nil
+	ctx.log(slog.LevelDebug, "surface", "EGL surface created", nil)
+	ctx.surfaceCreated()
 	return true
 }
 
 // auto match
 func (ctx *EGLContext) InitEGLSurface_XX() bool {
 	if !Initialize(ctx.display) {
-		log.Println("EGL initialize failed")
+		ctx.log(slog.LevelError, "initialize", "EGL initialize failed", nil)
 		return false
 	}
 
@@ -228,24 +409,24 @@ func (ctx *EGLContext) InitEGLSurface_XX() bool {
 }
 
 func (ctx *EGLContext) InitEGLContext() bool {
-	context_attribs := []EGLint{
-		CONTEXT_CLIENT_VERSION, 2, //Request opengl ES2.0
-		CONTEXT_MAJOR_VERSION, 2,
-		CONTEXT_MINOR_VERSION, 0,
-		NONE, NONE}
-	if ctx.esMajor >= 3 {
-		context_attribs[1] = ctx.esMajor
-		context_attribs[3] = ctx.esMajor
-		context_attribs[5] = ctx.esMinor
-	} else {
-		context_attribs[2] = NONE
+	shared := NO_CONTEXT
+	if ctx.share != nil {
+		shared = ctx.share.context
+	}
+	if err := ctx.createContext(shared); err != nil {
+		ctx.log(slog.LevelError, "context", "EGL create context failed", err)
+		ctx.err = err
+		return false
 	}
-	ctx.context = CreateContext(ctx.display, ctx.config, nil, context_attribs)
 
-	if !MakeCurrent(ctx.display, ctx.surface, ctx.surface, ctx.context) {
-		log.Println("Unable to eglMakeCurrent")
+	if err := MakeCurrentErr(ctx.display, ctx.surface, ctx.surface, ctx.context); err != nil {
+		ctx.log(slog.LevelError, "context", "Unable to eglMakeCurrent", err)
+		ctx.err = err
 		return false
 	}
+	ctx.draw, ctx.read = nil, nil
+	ctx.checkVersion()
+	ctx.updateSize()
 	return true
 }
 
@@ -260,20 +441,48 @@ func (ctx *EGLContext) ReinitEGLContext() bool {
 }
 
 func (ctx *EGLContext) SwapBuffers() bool {
-	b := SwapBuffers(ctx.display, ctx.surface)
-	if !b {
-		err := GetError()
-		if err == BAD_SURFACE {
-			//Recreate surface
-			ctx.InitEGLSurface()
-			return true //Still consider glContext is valid
-		} else if err == CONTEXT_LOST || err == BAD_CONTEXT {
-			//Context has been lost!!
-			ctx.Terminate()
-			ctx.InitEGLContext()
-		}
+	if ctx.surfaceless {
+		return true
+	}
+	if SwapBuffers(ctx.display, ctx.surface) {
+		ctx.updateSize()
+		return true
+	}
+	return ctx.swapFailed(GetError())
+}
+
+// swapFailed recovers from a failed swap of the surface and tells whether
+// the context is still valid.
+func (ctx *EGLContext) swapFailed(err Error) bool {
+	ctx.log(slog.LevelWarn, "swap", "EGL swap buffers failed", err)
+	if err == BAD_SURFACE {
+		//Recreate surface, the context stays valid if that works
+		return ctx.recreateSurface()
+	} else if err == CONTEXT_LOST || err == BAD_CONTEXT {
+		//Context has been lost!!
+		ctx.Recover()
+	}
+	return false
+}
+
+// recreateSurface replaces a surface that went bad and makes the context
+// current on the new one.
+func (ctx *EGLContext) recreateSurface() bool {
+	DestroySurface(ctx.display, ctx.surface)
+	ctx.surface = NO_SURFACE
+	ctx.surfaceLost()
+	surface, err := ctx.createSurface()
+	if err != nil {
+		ctx.err = err
+		return false
+	}
+	ctx.surface = surface
+	if err := MakeCurrentErr(ctx.display, ctx.surface, ctx.surface, ctx.context); err != nil {
+		ctx.err = err
 		return false
 	}
+	ctx.draw, ctx.read = nil, nil
+	ctx.surfaceCreated()
 	return true
 }
 
@@ -284,52 +493,79 @@ func (ctx *EGLContext) Terminate() {
 			DestroyContext(ctx.display, ctx.context)
 		}
 
+		for _, d := range ctx.drawables {
+			DestroySurface(ctx.display, d.surface)
+			d.surface = NO_SURFACE
+		}
 		if ctx.surface != NO_SURFACE {
 			DestroySurface(ctx.display, ctx.surface)
 		}
-		Terminate(ctx.display)
+		// the display belongs to the context we share with
+		if ctx.share == nil {
+			Terminate(ctx.display)
+		}
 	}
 
 	ctx.display = NO_DISPLAY
 	ctx.context = NO_CONTEXT
 	ctx.surface = NO_SURFACE
+	ctx.width, ctx.height = 0, 0
+	ctx.drawables = nil
+	ctx.draw, ctx.read = nil, nil
 }
 
+// Resume creates the surface again after Suspend and makes the context
+// current on it. If the context was lost meanwhile, Resume recovers it,
+// see Recover. Other failures are left to the caller, see Err.
 func (ctx *EGLContext) Resume() bool {
 	//Create surface
-	log.Println("EGLContext.Resume...")
-	ctx.surface = CreateWindowSurface(ctx.display, ctx.config, ctx.window, nil)
-	//screen_width := QuerySurface(display_, surface_, WIDTH, &screen_width_)
-	//screen_height := QuerySurface(display_, surface_, HEIGHT, &screen_height_)
-
-	if MakeCurrent(ctx.display, ctx.surface, ctx.surface, ctx.context) {
-		return true
+	surface, err := ctx.createSurface()
+	if err != nil {
+		ctx.log(slog.LevelError, "resume", "EGL create surface failed", err)
+		ctx.err = err
+		return false
 	}
+	ctx.surface = surface
 
-	err := GetError()
-	log.Printf("Unable to eglMakeCurrent %d\n", err)
-
-	if err == CONTEXT_LOST {
-		//Recreate context
-		log.Println("Re-creating egl context")
-		ctx.InitEGLContext()
-	} else {
-		//Recreate surface
-		ctx.Terminate()
-		ctx.InitEGLSurface()
-		ctx.InitEGLContext()
+	if err := MakeCurrentErr(ctx.display, ctx.surface, ctx.surface, ctx.context); err != nil {
+		ctx.log(slog.LevelWarn, "resume", "Unable to eglMakeCurrent", err)
+		if code := ErrorCode(err); code == CONTEXT_LOST || code == BAD_CONTEXT {
+			//Recreate display, surface and context
+			return ctx.Recover()
+		}
+		ctx.err = err
+		return false
 	}
+	ctx.err = nil
+	ctx.draw, ctx.read = nil, nil
+	ctx.log(slog.LevelDebug, "resume", "EGL surface created", nil)
+	ctx.surfaceCreated()
 	return true
 }
 
 func (ctx *EGLContext) Suspend() {
 	if ctx.surface != NO_SURFACE {
-		log.Println("EGLContext.Suspend...")
+		ctx.log(slog.LevelDebug, "suspend", "EGL surface destroyed", nil)
 		DestroySurface(ctx.display, ctx.surface)
 		ctx.surface = NO_SURFACE
+		ctx.surfaceLost()
 	}
 }
 
+// Size queries the current size of the surface, 0x0 if there is none.
+// SwapBuffers reports size changes to the observer's OnResize.
+func (ctx *EGLContext) Size() (width, height int) {
+	if ctx.surface == NO_SURFACE {
+		return 0, 0
+	}
+	w, _ := QuerySurface(ctx.display, ctx.surface, WIDTH)
+	h, _ := QuerySurface(ctx.display, ctx.surface, HEIGHT)
+	return int(w), int(h)
+}
+
 func (ctx *EGLContext) IsReady() bool {
+	if ctx.surfaceless {
+		return NO_CONTEXT != ctx.context
+	}
 	return NO_SURFACE != ctx.surface
 }
@@ -0,0 +1,295 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gooid/gl/egl"
+	"github.com/gooid/gl/egl/egltest"
+)
+
+func TestInitEGLSurfaceDepthFallback(t *testing.T) {
+	b := egltest.New()
+	b.Configs = egltest.DefaultConfigs()[:1] // 5/6/5, 16 bit depth
+	defer b.Install()()
+
+	ctx := egl.NewContextEx(egltest.Window(1), nil, 24, 2, 0)
+	defer ctx.Terminate()
+	if !ctx.InitEGLSurface() {
+		t.Fatalf("InitEGLSurface: %v", ctx.Err())
+	}
+	info, err := ctx.ConfigInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.DepthSize != 16 {
+		t.Errorf("depth size = %d, want the 16 bit fallback", info.DepthSize)
+	}
+}
+
+func TestInitEGLSurfaceRequired(t *testing.T) {
+	b := egltest.New()
+	b.Configs = egltest.DefaultConfigs()[:1]
+	defer b.Install()()
+
+	ctx := egl.NewContextEx(egltest.Window(1), nil, 24, 2, 0)
+	defer ctx.Terminate()
+	spec := ctx.ConfigSpec()
+	spec.Required = egl.ConfigDepth
+	ctx.SetConfigSpec(spec)
+	if ctx.InitEGLSurface() {
+		t.Fatal("InitEGLSurface accepted a config without the required depth")
+	}
+	var noConfig *egl.NoConfigError
+	if !errors.As(ctx.Err(), &noConfig) {
+		t.Fatalf("Err() = %v, want a *NoConfigError", ctx.Err())
+	}
+	if s, _ := b.Live(); s != 0 {
+		t.Errorf("%d surfaces created without a config", s)
+	}
+}
+
+func TestInitEGLSurfaceConfigQueryError(t *testing.T) {
+	b := egltest.New()
+	defer b.Install()()
+
+	ctx := egl.NewContext(egltest.Window(1), nil)
+	defer ctx.Terminate()
+	b.Fail("eglGetConfigAttrib", egl.BAD_CONFIG, -1)
+	if ctx.InitEGLSurface() {
+		t.Fatal("InitEGLSurface chose a config that cannot be queried")
+	}
+	var noConfig *egl.NoConfigError
+	if !errors.As(ctx.Err(), &noConfig) {
+		t.Fatalf("Err() = %v, want a *NoConfigError", ctx.Err())
+	}
+	for _, c := range noConfig.Rejected {
+		if !strings.Contains(c.Reject, "eglGetConfigAttrib") {
+			t.Errorf("config rejected for %q, want the query error", c.Reject)
+		}
+	}
+}
+
+func TestInitEGLSurfaceBadWindow(t *testing.T) {
+	b := egltest.New()
+	defer b.Install()()
+
+	ctx := egl.NewContext(nil, nil)
+	defer ctx.Terminate()
+	if ctx.InitEGLSurface() {
+		t.Fatal("InitEGLSurface succeeded without a window")
+	}
+	if code := egl.ErrorCode(ctx.Err()); code != egl.BAD_NATIVE_WINDOW {
+		t.Errorf("Err() = %v, want BAD_NATIVE_WINDOW", ctx.Err())
+	}
+}
+
+// currentObserver checks that OnResize is called with the context current.
+type currentObserver struct {
+	egl.NopObserver
+	t       *testing.T
+	b       *egltest.Backend
+	resizes int
+}
+
+func (o *currentObserver) OnResize(ctx *egl.EGLContext, width, height int) {
+	o.resizes++
+	if c, _, _ := o.b.Current(); c == egl.NO_CONTEXT || c != ctx.Context() {
+		o.t.Errorf("OnResize(%d, %d) without the context current", width, height)
+	}
+}
+
+func TestOnResizeContextCurrent(t *testing.T) {
+	b := egltest.New()
+	defer b.Install()()
+
+	win := egltest.Window(1)
+	ctx := egl.NewContext(win, nil)
+	defer ctx.Terminate()
+	obs := &currentObserver{t: t, b: b}
+	ctx.SetObserver(obs)
+	if !ctx.InitEGLSurface() || !ctx.InitEGLContext() {
+		t.Fatalf("init: %v", ctx.Err())
+	}
+	if obs.resizes != 1 {
+		t.Errorf("%d resizes after init, want 1", obs.resizes)
+	}
+
+	b.Resize(win, 800, 600)
+	if !ctx.SwapBuffers() {
+		t.Fatal("SwapBuffers failed")
+	}
+	if w, h := ctx.Size(); w != 800 || h != 600 {
+		t.Errorf("Size() = %dx%d, want 800x600", w, h)
+	}
+
+	b.LoseContexts()
+	ctx.SwapBuffers()
+	if obs.resizes != 3 {
+		t.Errorf("%d resizes after resize and recovery, want 3", obs.resizes)
+	}
+}
+
+func TestSuspendResume(t *testing.T) {
+	b := egltest.New()
+	ctx, rec := newContext(t, b)
+
+	ctx.Suspend()
+	if ctx.IsReady() {
+		t.Error("suspended context is ready")
+	}
+	if !ctx.Resume() {
+		t.Fatalf("Resume: %v", ctx.Err())
+	}
+	checkEvents(t, rec, "surface lost", "surface created")
+	if !ctx.SwapBuffers() {
+		t.Fatal("SwapBuffers after Resume failed")
+	}
+	if b.Count("eglCreateContext") != 1 {
+		t.Error("Resume recreated the context")
+	}
+	if s, c := b.Live(); s != 1 || c != 1 {
+		t.Errorf("live surfaces, contexts = %d, %d, want 1, 1", s, c)
+	}
+}
+
+func TestSwapBuffersWithDamageFallback(t *testing.T) {
+	b := egltest.New()
+	b.Extensions = []egl.Extension{egl.KHR_swap_buffers_with_damage}
+	ctx, _ := newContext(t, b)
+
+	if p := egl.Procs(); p != (egl.ProcSet{}) {
+		t.Errorf("Procs() = %+v under the fake, want none", p)
+	}
+	var procErr *egl.ProcError
+	if err := egl.SwapBuffersWithDamageKHR(ctx.Display(), egl.NO_SURFACE, nil); !errors.As(err, &procErr) {
+		t.Errorf("SwapBuffersWithDamageKHR = %v, want a *ProcError", err)
+	}
+	if !ctx.SwapBuffersWithDamage([]egl.Rect{{X: 0, Y: 0, Width: 8, Height: 8}}) {
+		t.Fatal("SwapBuffersWithDamage failed")
+	}
+	if n := b.Count("eglSwapBuffers"); n != 1 {
+		t.Errorf("%d eglSwapBuffers, want the plain swap fallback", n)
+	}
+}
+
+func TestBackendSwitchFlushesExtensions(t *testing.T) {
+	b := egltest.New()
+	b.ClientExtensions = []egl.Extension{egl.EXT_platform_base}
+	restore := b.Install()
+	if !egl.ClientExtensions().Has(egl.EXT_platform_base) {
+		t.Error("client extensions of the fake not reported")
+	}
+	restore()
+
+	defer egltest.New().Install()()
+	if egl.ClientExtensions().Has(egl.EXT_platform_base) {
+		t.Error("client extensions of a previous backend leaked")
+	}
+}
+
+func TestContextOptionsMajorOnly(t *testing.T) {
+	b := egltest.New() // EGL 1.4 without EGL_KHR_create_context
+	b.ESMajor, b.ESMinor = 2, 0
+	defer b.Install()()
+
+	ctx := egl.NewContext(egltest.Window(1), nil)
+	defer ctx.Terminate()
+	ctx.SetContextOptions(egl.ContextOptions{Major: 3, Minor: 2, Fallback: true})
+	if !ctx.InitEGLSurface() || !ctx.InitEGLContext() {
+		t.Fatalf("init: %v", ctx.Err())
+	}
+	if n := b.Count("eglCreateContext"); n != 2 {
+		t.Errorf("%d eglCreateContext, want one for 3.x and one for 2.0", n)
+	}
+	if got := ctx.ObtainedContextOptions(); got.Major != 2 || got.Minor != 0 {
+		t.Errorf("obtained %d.%d, want 2.0", got.Major, got.Minor)
+	}
+}
+
+func TestContextOptionsMinor(t *testing.T) {
+	b := egltest.New()
+	b.Minor = 5
+	b.ESMinor = 1
+	defer b.Install()()
+
+	ctx := egl.NewContext(egltest.Window(1), nil)
+	defer ctx.Terminate()
+	ctx.SetContextOptions(egl.ContextOptions{Major: 3, Minor: 2, Fallback: true})
+	if !ctx.InitEGLSurface() || !ctx.InitEGLContext() {
+		t.Fatalf("init: %v", ctx.Err())
+	}
+	if got := ctx.ObtainedContextOptions(); got.Major != 3 || got.Minor != 1 {
+		t.Errorf("obtained %d.%d, want 3.1", got.Major, got.Minor)
+	}
+}
+
+func TestPbufferContextMultisample(t *testing.T) {
+	b := egltest.New()
+	defer b.Install()()
+
+	ctx := egl.CreatePbufferEGLContextMultisample(nil, 8, 8, 24, 8, 0, 2)
+	if ctx == nil {
+		t.Fatal("CreatePbufferEGLContextMultisample failed")
+	}
+	defer ctx.Terminate()
+	info, err := ctx.ConfigInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.StencilSize != 8 || info.Samples != 0 {
+		t.Errorf("stencil %d, samples %d, want 8 and none", info.StencilSize, info.Samples)
+	}
+
+	ms := egl.NewPbufferContext(nil, 8, 8, 24, 2, 0)
+	defer ms.Terminate()
+	if !ms.InitEGLSurface() {
+		t.Fatalf("InitEGLSurface: %v", ms.Err())
+	}
+	if info, _ := ms.ConfigInfo(); info.Samples != 4 {
+		t.Errorf("NewPbufferContext samples = %d, want 4", info.Samples)
+	}
+}
+
+func TestContextOptionsForwardES(t *testing.T) {
+	b := egltest.New()
+	b.Minor = 5
+	defer b.Install()()
+
+	ctx := egl.NewContext(egltest.Window(1), nil)
+	defer ctx.Terminate()
+	ctx.SetContextOptions(egl.ContextOptions{Major: 2, Forward: true})
+	if !ctx.InitEGLSurface() || !ctx.InitEGLContext() {
+		t.Fatalf("init: %v", ctx.Err())
+	}
+	if n := b.Count("eglCreateContext"); n != 1 {
+		t.Errorf("%d eglCreateContext, want the flag left out at once", n)
+	}
+	if ctx.ObtainedContextOptions().Forward {
+		t.Error("forward compatible ES context reported")
+	}
+}
+
+func TestSwapBuffersWithoutObserver(t *testing.T) {
+	b := egltest.New()
+	ctx, _ := newContext(t, b)
+	ctx.SetObserver(nil)
+
+	n := b.Count("eglQuerySurface")
+	for i := 0; i < 3; i++ {
+		if !ctx.SwapBuffers() {
+			t.Fatal("SwapBuffers failed")
+		}
+	}
+	if q := b.Count("eglQuerySurface") - n; q != 0 {
+		t.Errorf("%d eglQuerySurface for swaps without an observer", q)
+	}
+	if w, h := ctx.Size(); w != 640 || h != 480 {
+		t.Errorf("Size() = %dx%d, want 640x480", w, h)
+	}
+}
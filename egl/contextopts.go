@@ -0,0 +1,254 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+/*
+typedef const unsigned char *(*GPGETSTRING)(unsigned int name);
+
+static const char *glowGetString(GPGETSTRING fnptr, unsigned int name) {
+	return (const char *)(*fnptr)(name);
+}
+*/
+import "C"
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// EGL_IMG_context_priority
+const (
+	CONTEXT_PRIORITY_LEVEL_IMG  = 0x3100
+	CONTEXT_PRIORITY_HIGH_IMG   = 0x3101
+	CONTEXT_PRIORITY_MEDIUM_IMG = 0x3102
+	CONTEXT_PRIORITY_LOW_IMG    = 0x3103
+)
+
+// EGL_KHR_create_context_no_error
+const (
+	CONTEXT_OPENGL_NO_ERROR_KHR = 0x31B3
+)
+
+// EGL 1.5, for desktop OpenGL contexts only, see ContextOptions.Forward
+const (
+	CONTEXT_OPENGL_FORWARD_COMPATIBLE = 0x31B1
+)
+
+// ContextOptions describes the context created by InitEGLContext.
+// Options the display does not support are left out; ObtainedContextOptions
+// tells what was granted.
+type ContextOptions struct {
+	// Major and Minor are the OpenGL ES version wanted.
+	Major, Minor int
+	// Fallback tries the lower versions of 3.2, 3.1, 3.0 and 2.0 in turn
+	// when the wanted one cannot be created. Without EGL 1.5 or
+	// EGL_KHR_create_context only the major version can be asked for.
+	Fallback bool
+	// Priority is one of the CONTEXT_PRIORITY_*_IMG levels, 0 for the
+	// default (EGL_IMG_context_priority). The driver may grant another.
+	Priority int
+	// NoError creates a context without GL error checking
+	// (EGL_KHR_create_context_no_error). It is ignored with Debug or
+	// Robust, which it conflicts with.
+	NoError bool
+	// Debug creates a debug context, see SetDebug.
+	Debug bool
+	// Forward creates a forward compatible context without the deprecated
+	// features. It only applies to desktop OpenGL, with OPENGL_API bound;
+	// OpenGL ES contexts ignore it, as EGL rejects the flag for them.
+	Forward bool
+	// Robust creates a robust context, see SetRobust.
+	Robust bool
+}
+
+// esVersions is the fallback chain of ContextOptions.Fallback.
+var esVersions = [][2]int{{3, 2}, {3, 1}, {3, 0}, {2, 0}}
+
+// versions returns the versions to try, best first.
+func (opts ContextOptions) versions() [][2]int {
+	want := [2]int{opts.Major, opts.Minor}
+	if !opts.Fallback {
+		return [][2]int{want}
+	}
+	list := [][2]int{want}
+	for _, v := range esVersions {
+		if v[0] < want[0] || v[0] == want[0] && v[1] < want[1] {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// attribs returns the attribute list for version v, without the NONE
+// terminator, and the options it asks for. The minor version is only
+// asked for where EGL can express it.
+func (opts ContextOptions) attribs(ctx *EGLContext, v [2]int, extras bool) ([]EGLint, ContextOptions) {
+	got := ContextOptions{Major: v[0]}
+	core := isCore15(ctx.display)
+	ext := ctx.Extensions()
+	createContext := core || ext.Has(KHR_create_context)
+
+	list := []EGLint{CONTEXT_CLIENT_VERSION, EGLint(v[0])}
+	if v[1] != 0 && createContext {
+		list = append(list, CONTEXT_MINOR_VERSION, EGLint(v[1]))
+		got.Minor = v[1]
+	}
+	if !extras {
+		return list, got
+	}
+
+	if opts.Robust && ext.Has(EXT_create_context_robustness) {
+		list = append(list, robustAttribs...)
+		got.Robust = true
+	}
+	forward := opts.Forward && QueryAPI() == OPENGL_API
+	if opts.Debug && core {
+		list = append(list, CONTEXT_OPENGL_DEBUG, TRUE)
+		got.Debug = true
+	}
+	if forward && core {
+		list = append(list, CONTEXT_OPENGL_FORWARD_COMPATIBLE, TRUE)
+		got.Forward = true
+	}
+	if !core && ext.Has(KHR_create_context) {
+		var flags EGLint
+		if opts.Debug {
+			flags |= CONTEXT_OPENGL_DEBUG_BIT_KHR
+			got.Debug = true
+		}
+		if forward {
+			flags |= CONTEXT_OPENGL_FORWARD_COMPATIBLE_BIT_KHR
+			got.Forward = true
+		}
+		if flags != 0 {
+			list = append(list, CONTEXT_FLAGS_KHR, flags)
+		}
+	}
+	if opts.NoError && !opts.Debug && !opts.Robust && ext.Has(KHR_create_context_no_error) {
+		list = append(list, CONTEXT_OPENGL_NO_ERROR_KHR, TRUE)
+		got.NoError = true
+	}
+	if opts.Priority != 0 && ext.Has(IMG_context_priority) {
+		list = append(list, CONTEXT_PRIORITY_LEVEL_IMG, EGLint(opts.Priority))
+		got.Priority = opts.Priority
+	}
+	return list, got
+}
+
+// ContextOptions returns the options of the context to be created.
+func (ctx *EGLContext) ContextOptions() ContextOptions {
+	return ctx.opts
+}
+
+// SetContextOptions changes the context to be created by InitEGLContext.
+// The config is still chosen for the version given to the constructor;
+// adjust ConfigSpec.RenderableType when asking for another major version.
+func (ctx *EGLContext) SetContextOptions(opts ContextOptions) {
+	ctx.opts = opts
+}
+
+// ObtainedContextOptions returns the options granted to the context
+// created by InitEGLContext, including the version that was obtained as
+// reported by the context once current. The minor version is 0 when it
+// was neither asked for nor readable from GL_VERSION.
+func (ctx *EGLContext) ObtainedContextOptions() ContextOptions {
+	return ctx.obtained
+}
+
+// createContext creates the context, walking the version fallback chain
+// and, for each version, retrying without the optional attributes that
+// drivers may refuse for some configs.
+func (ctx *EGLContext) createContext(shared Context) error {
+	var err error
+	var asked [][]EGLint
+	for _, v := range ctx.opts.versions() {
+		full, got := ctx.opts.attribs(ctx, v, true)
+		plain, plainGot := ctx.opts.attribs(ctx, v, false)
+		// 3.2, 3.1 and 3.0 are one request when the minor version
+		// cannot be asked for
+		if containsList(asked, plain) {
+			continue
+		}
+		asked = append(asked, plain)
+		tries := [][]EGLint{full}
+		if len(plain) < len(full) {
+			tries = append(tries, plain)
+		}
+		for i, attribs := range tries {
+			var c Context
+			c, err = CreateContextErr(ctx.display, ctx.config, shared, append(attribs, NONE))
+			if err != nil {
+				ctx.log(slog.LevelWarn, "context", "EGL create context failed", err)
+				continue
+			}
+			if i > 0 {
+				got = plainGot
+			}
+			if got.Priority != 0 {
+				if p, err := QueryContextErr(ctx.display, c, CONTEXT_PRIORITY_LEVEL_IMG); err == nil {
+					got.Priority = int(p)
+				}
+			}
+			ctx.context = c
+			ctx.obtained = got
+			return nil
+		}
+	}
+	return err
+}
+
+// checkVersion corrects the obtained version with what the current
+// context reports: EGL_CONTEXT_CLIENT_VERSION for the major version and
+// GL_VERSION, where glGetString can be resolved, for both.
+func (ctx *EGLContext) checkVersion() {
+	if v, err := QueryContextErr(ctx.display, ctx.context, CONTEXT_CLIENT_VERSION); err == nil && int(v) != ctx.obtained.Major {
+		ctx.obtained.Major, ctx.obtained.Minor = int(v), 0
+	}
+	// core functions need EGL 1.5 or EGL_KHR_get_all_proc_addresses
+	if !isCore15(ctx.display) && !ctx.Extensions().Has(KHR_get_all_proc_addresses) &&
+		!ClientExtensions().Has(KHR_client_get_all_proc_addresses) {
+		return
+	}
+	if major, minor, ok := glVersion(); ok {
+		ctx.obtained.Major, ctx.obtained.Minor = major, minor
+	}
+}
+
+const glVERSION = 0x1F02
+
+// glVersion returns the version of the OpenGL ES context current on the
+// calling thread from its GL_VERSION, "OpenGL ES <major>.<minor> ...".
+func glVersion() (major, minor int, ok bool) {
+	fn := GetProcAddress("glGetString")
+	if fn == nil {
+		return 0, 0, false
+	}
+	s := C.glowGetString((C.GPGETSTRING)(fn), glVERSION)
+	if s == nil {
+		return 0, 0, false
+	}
+	v := C.GoString(s)
+	if !strings.HasPrefix(v, "OpenGL ES ") {
+		return 0, 0, false
+	}
+	return parseVersion(strings.TrimPrefix(v, "OpenGL ES "))
+}
+
+// containsList reports whether lists holds an attribute list equal to l.
+func containsList(lists [][]EGLint, l []EGLint) bool {
+	for _, m := range lists {
+		if len(m) != len(l) {
+			continue
+		}
+		equal := true
+		for i := range m {
+			equal = equal && m[i] == l[i]
+		}
+		if equal {
+			return true
+		}
+	}
+	return false
+}
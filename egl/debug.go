@@ -0,0 +1,28 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+// EGL 1.5 / EGL_KHR_create_context
+const (
+	CONTEXT_OPENGL_DEBUG                      = 0x31B0
+	CONTEXT_FLAGS_KHR                         = 0x30FC
+	CONTEXT_OPENGL_DEBUG_BIT_KHR              = 0x0001
+	CONTEXT_OPENGL_FORWARD_COMPATIBLE_BIT_KHR = 0x0002
+	CONTEXT_OPENGL_ROBUST_ACCESS_BIT_KHR      = 0x0004
+)
+
+// SetDebug asks InitEGLContext for a debug context, which validates GL
+// calls thoroughly and reports problems through GL_KHR_debug (see
+// DebugMessageCallbackKHR in es2). It needs EGL 1.5 or
+// EGL_KHR_create_context; otherwise, or if the driver refuses, a regular
+// context is created, see IsDebug.
+func (ctx *EGLContext) SetDebug(debug bool) {
+	ctx.opts.Debug = debug
+}
+
+// IsDebug reports whether the context was created as a debug context.
+func (ctx *EGLContext) IsDebug() bool {
+	return ctx.obtained.Debug
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import (
+	"errors"
+	"strings"
+	"unsafe"
+)
+
+// Device is an EGLDeviceEXT handle.
+type Device unsafe.Pointer
+
+var NO_DEVICE Device
+
+// EGL_EXT_device_base / device_query / device_drm
+const (
+	BAD_DEVICE_EXT           = 0x322B
+	DEVICE_EXT               = 0x322C
+	DRM_DEVICE_FILE_EXT      = 0x3233
+	DRM_MASTER_FD_EXT        = 0x333C
+	DRM_RENDER_NODE_FILE_EXT = 0x3377
+)
+
+// ErrNoDeviceEnumeration is returned when the client lacks
+// EGL_EXT_device_enumeration or EGL_EXT_device_query.
+var ErrNoDeviceEnumeration = errors.New("egl: EGL_EXT_device_enumeration is not supported")
+
+func hasDeviceQuery() bool {
+	exts := ClientExtensions()
+	return exts.Has(EXT_device_query) || exts.Has(EXT_device_base)
+}
+
+// QueryDevices returns every device the client can render with.
+func QueryDevices() ([]Device, error) {
+	if exts := ClientExtensions(); !exts.Has(EXT_device_enumeration) && !exts.Has(EXT_device_base) {
+		return nil, ErrNoDeviceEnumeration
+	}
+	n, err := QueryDevicesEXT(nil)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	devs := make([]Device, n)
+	n, err = QueryDevicesEXT(devs)
+	if err != nil {
+		return nil, err
+	}
+	return devs[:n], nil
+}
+
+// QueryDeviceString returns the string attribute name (EXTENSIONS,
+// DRM_DEVICE_FILE_EXT, DRM_RENDER_NODE_FILE_EXT) of dev, or an empty
+// string if the device does not have it.
+func QueryDeviceString(dev Device, name int) string {
+	if !hasDeviceQuery() {
+		return ""
+	}
+	s, _ := QueryDeviceStringEXT(dev, name)
+	return s
+}
+
+// DeviceExtensions returns the device extensions of dev.
+func DeviceExtensions(dev Device) []string {
+	return strings.Fields(QueryDeviceString(dev, EXTENSIONS))
+}
+
+// DeviceDRMFile returns the DRM primary node of dev, e.g.
+// /dev/dri/card0, if the device supports EGL_EXT_device_drm.
+func DeviceDRMFile(dev Device) string {
+	return QueryDeviceString(dev, DRM_DEVICE_FILE_EXT)
+}
+
+// DeviceDRMRenderNode returns the DRM render node of dev, e.g.
+// /dev/dri/renderD128, if it supports EGL_EXT_device_drm_render_node.
+func DeviceDRMRenderNode(dev Device) string {
+	return QueryDeviceString(dev, DRM_RENDER_NODE_FILE_EXT)
+}
+
+// QueryDisplayDevice returns the device a display renders with.
+func QueryDisplayDevice(d Display) (Device, error) {
+	if !hasDeviceQuery() {
+		return NO_DEVICE, ErrNoDeviceEnumeration
+	}
+	return queryDisplayDeviceEXT(d)
+}
+
+// GetDeviceDisplay returns the display rendering with dev.
+func GetDeviceDisplay(dev Device) (Display, error) {
+	return GetPlatformDisplayErr(PLATFORM_DEVICE_EXT, unsafe.Pointer(dev), nil)
+}
+
+// SetDevice makes the context render with dev, see SetPlatformDisplay.
+func (ctx *EGLContext) SetDevice(dev Device) bool {
+	return ctx.SetPlatformDisplay(PLATFORM_DEVICE_EXT, unsafe.Pointer(dev), nil)
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import "errors"
+
+// Drawable is an additional window or pbuffer surface rendered to with the
+// context of an EGLContext. It uses the config of the EGLContext, which
+// must support the surface type (see ConfigSpec.SurfaceType).
+type Drawable struct {
+	ctx     *EGLContext
+	surface Surface
+	window  NativeWindow
+}
+
+var errDetached = errors.New("egl: drawable is detached")
+
+// AttachWindow creates a window surface for win.
+func (ctx *EGLContext) AttachWindow(win NativeWindow) (*Drawable, error) {
+	s, err := ctx.createWindowSurface(win)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.attach(&Drawable{ctx: ctx, surface: s, window: win}), nil
+}
+
+// AttachPbuffer creates a width x height pbuffer surface.
+func (ctx *EGLContext) AttachPbuffer(width, height int) (*Drawable, error) {
+	s, err := ctx.createPbuffer(EGLint(width), EGLint(height))
+	if err != nil {
+		return nil, err
+	}
+	return ctx.attach(&Drawable{ctx: ctx, surface: s}), nil
+}
+
+func (ctx *EGLContext) attach(d *Drawable) *Drawable {
+	ctx.drawables = append(ctx.drawables, d)
+	return d
+}
+
+// MakeCurrent makes the context current on the calling thread with draw
+// and read as draw and read surfaces. A nil Drawable stands for the
+// surface created by InitEGLSurface.
+func (ctx *EGLContext) MakeCurrent(draw, read *Drawable) error {
+	ds, rs := ctx.surface, ctx.surface
+	if draw != nil {
+		if draw.surface == NO_SURFACE {
+			return errDetached
+		}
+		ds = draw.surface
+	}
+	if read != nil {
+		if read.surface == NO_SURFACE {
+			return errDetached
+		}
+		rs = read.surface
+	}
+	if err := MakeCurrentErr(ctx.display, ds, rs, ctx.context); err != nil {
+		return err
+	}
+	ctx.draw, ctx.read = draw, read
+	return nil
+}
+
+// Surface returns the EGL surface of the drawable.
+func (d *Drawable) Surface() Surface {
+	return d.surface
+}
+
+// Window returns the native window of a window drawable, nil for a
+// pbuffer.
+func (d *Drawable) Window() NativeWindow {
+	return d.window
+}
+
+// Size returns the current size of the surface, 0x0 once detached.
+func (d *Drawable) Size() (width, height int) {
+	if d.surface == NO_SURFACE {
+		return 0, 0
+	}
+	w, _ := QuerySurface(d.ctx.display, d.surface, WIDTH)
+	h, _ := QuerySurface(d.ctx.display, d.surface, HEIGHT)
+	return int(w), int(h)
+}
+
+// Swap posts the back buffer of the drawable to its window. It is a no-op
+// for pbuffers. The context must be current on the calling thread, though
+// not necessarily on this drawable.
+func (d *Drawable) Swap() error {
+	if d.surface == NO_SURFACE {
+		return errDetached
+	}
+	return SwapBuffersErr(d.ctx.display, d.surface)
+}
+
+// Detach destroys the surface. If the drawable is current, the context is
+// made current on its primary surface first; if that fails the drawable
+// stays attached and the error is returned.
+func (d *Drawable) Detach() error {
+	ctx := d.ctx
+	if d.surface == NO_SURFACE {
+		return nil
+	}
+	if ctx.draw == d || ctx.read == d {
+		if err := ctx.MakeCurrent(nil, nil); err != nil {
+			return err
+		}
+	}
+	for i, o := range ctx.drawables {
+		if o == d {
+			ctx.drawables = append(ctx.drawables[:i], ctx.drawables[i+1:]...)
+			break
+		}
+	}
+	err := DestroySurfaceErr(ctx.display, d.surface)
+	d.surface = NO_SURFACE
+	return err
+}
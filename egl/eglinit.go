@@ -5,7 +5,7 @@
 package egl
 
 import (
-	"log"
+	"log/slog"
 	"unsafe"
 )
 
@@ -21,27 +21,69 @@ func CreateEGLContext(native NativeObj) *EGLContext {
 }
 
 func CreateEGLContextEx(native NativeObj, depthSize, esVersion int) *EGLContext {
-	eglctx := NewContextEx(NativeWindow(native.NativeWindow()),
-		NativeDisplay(native.NativeDisplay()), depthSize, esVersion, 0)
-	log.Println("EGL InitEGLSurface...")
+	return initNativeContext(native, NewContextEx(NativeWindow(native.NativeWindow()),
+		NativeDisplay(native.NativeDisplay()), depthSize, esVersion, 0))
+}
+
+// CreateEGLContextMultisample is CreateEGLContextEx with an explicit
+// stencil size and sample count, see NewContextMultisample for the
+// fallbacks.
+func CreateEGLContextMultisample(native NativeObj, depthSize, stencilSize, samples, esVersion int) *EGLContext {
+	return initNativeContext(native, NewContextMultisample(NativeWindow(native.NativeWindow()),
+		NativeDisplay(native.NativeDisplay()), depthSize, stencilSize, samples, esVersion, 0))
+}
+
+// initNativeContext initializes the surface and context of eglctx and
+// makes it current. The buffers geometry of native, which may be nil for
+// offscreen contexts, is set to the format of the chosen config. On
+// failure the display is terminated and nil is returned.
+func initNativeContext(native NativeObj, eglctx *EGLContext) *EGLContext {
+	eglctx.log(slog.LevelDebug, "surface", "EGL InitEGLSurface...", nil)
 	if !eglctx.InitEGLSurface() {
-		log.Println("Init EGL Surface failed.", GetError())
+		eglctx.log(slog.LevelError, "surface", "Init EGL Surface failed", eglctx.Err())
+		eglctx.Terminate()
 		return nil
 	}
 
-	format := eglctx.GetFormat()
-	if native.SetBuffersGeometry(format) != 0 {
-		log.Println("EGL set buffers geometry failed.", GetError())
-		eglctx.Terminate()
-		return nil
+	if native != nil {
+		format := eglctx.GetFormat()
+		if native.SetBuffersGeometry(format) != 0 {
+			eglctx.log(slog.LevelError, "geometry", "EGL set buffers geometry failed", GetError())
+			eglctx.Terminate()
+			return nil
+		}
 	}
 
-	log.Println("EGL InitEGLContext...")
+	eglctx.log(slog.LevelDebug, "context", "EGL InitEGLContext...", nil)
 	if !eglctx.InitEGLContext() {
-		log.Println("Init EGL Context failed.", GetError())
+		eglctx.log(slog.LevelError, "context", "Init EGL Context failed", eglctx.Err())
 		eglctx.Terminate()
 		return nil
 	}
 
 	return eglctx
 }
+
+// CreatePbufferEGLContext creates and makes current a headless context
+// rendering into a width x height pbuffer.
+func CreatePbufferEGLContext(ndisplay NativeDisplay, width, height int) *EGLContext {
+	return CreatePbufferEGLContextEx(ndisplay, width, height, 16, 2)
+}
+
+func CreatePbufferEGLContextEx(ndisplay NativeDisplay, width, height, depthSize, esVersion int) *EGLContext {
+	return initNativeContext(nil, NewPbufferContext(ndisplay, width, height, depthSize, esVersion, 0))
+}
+
+// CreatePbufferEGLContextMultisample is CreatePbufferEGLContextEx with an
+// explicit stencil size and sample count, see NewContextMultisample for
+// the fallbacks.
+func CreatePbufferEGLContextMultisample(ndisplay NativeDisplay, width, height, depthSize, stencilSize, samples, esVersion int) *EGLContext {
+	return initNativeContext(nil, NewPbufferContextMultisample(ndisplay, width, height, depthSize, stencilSize, samples, esVersion, 0))
+}
+
+// CreateSurfacelessEGLContext creates and makes current a context without
+// any surface, see NewSurfacelessContext. It has no default framebuffer,
+// so stencil and multisampling belong to its framebuffer objects.
+func CreateSurfacelessEGLContext(ndisplay NativeDisplay, esVersion int) *EGLContext {
+	return initNativeContext(nil, NewSurfacelessContext(ndisplay, esVersion, 0))
+}
@@ -0,0 +1,1053 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package egltest provides a fake EGL implementation for testing code
+// built on package egl without a GPU or libEGL.
+//
+// A Backend simulates displays, configs, surfaces and contexts in Go and
+// can be told to fail calls, lose contexts or resize windows:
+//
+//	b := egltest.New()
+//	defer b.Install()()
+//
+//	ctx := egl.NewContext(egltest.Window(1), nil)
+//	b.Fail("eglSwapBuffers", egl.BAD_SURFACE, 1)
+//	ctx.InitEGLSurface()
+//	ctx.InitEGLContext()
+//	ctx.SwapBuffers() // recreates the surface
+//
+// The fake has a single current context for the whole process instead of
+// one per thread, and only implements the core EGL entry points.
+// GetProcAddress returns nil, egl.Procs reports no extension entry points
+// and their wrappers fail with *egl.ProcError.
+package egltest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/gooid/gl/egl"
+	"github.com/gooid/gl/egl/internal/backend"
+)
+
+// Config is a simulated framebuffer config: EGL attribute to value, e.g.
+// egl.RED_SIZE: 8. Attributes that are not set read as 0; CONFIG_ID
+// defaults to the position in Backend.Configs plus one.
+type Config map[int]int
+
+// DefaultConfigs returns the configs of New: a 5/6/5 config with a 16 bit
+// depth buffer, an 8/8/8/8 config with 24 bit depth and 8 bit stencil,
+// and the same with 4x multisampling. All support window and pbuffer
+// surfaces and OpenGL ES 2 and 3.
+func DefaultConfigs() []Config {
+	base := func(r, g, b, a, depth, stencil, samples int) Config {
+		sampleBuffers := 0
+		if samples > 0 {
+			sampleBuffers = 1
+		}
+		return Config{
+			egl.BUFFER_SIZE:      r + g + b + a,
+			egl.RED_SIZE:         r,
+			egl.GREEN_SIZE:       g,
+			egl.BLUE_SIZE:        b,
+			egl.ALPHA_SIZE:       a,
+			egl.DEPTH_SIZE:       depth,
+			egl.STENCIL_SIZE:     stencil,
+			egl.SAMPLES:          samples,
+			egl.SAMPLE_BUFFERS:   sampleBuffers,
+			egl.CONFIG_CAVEAT:    egl.NONE,
+			egl.SURFACE_TYPE:     egl.WINDOW_BIT | egl.PBUFFER_BIT,
+			egl.RENDERABLE_TYPE:  egl.OPENGL_ES2_BIT | egl.OPENGL_ES3_BIT,
+			egl.CONFORMANT:       egl.OPENGL_ES2_BIT | egl.OPENGL_ES3_BIT,
+			egl.NATIVE_VISUAL_ID: 1,
+		}
+	}
+	return []Config{
+		base(5, 6, 5, 0, 16, 0, 0),
+		base(8, 8, 8, 8, 24, 8, 0),
+		base(8, 8, 8, 8, 24, 8, 4),
+	}
+}
+
+type window struct {
+	id int
+}
+
+var (
+	windowsMu sync.Mutex
+	windows   = make(map[int]*window)
+)
+
+// Window returns the fake native window handle for id. The same id always
+// returns the same handle.
+func Window(id int) egl.NativeWindow {
+	windowsMu.Lock()
+	defer windowsMu.Unlock()
+	w := windows[id]
+	if w == nil {
+		w = &window{id: id}
+		windows[id] = w
+	}
+	return egl.NativeWindow(unsafe.Pointer(w))
+}
+
+type display struct {
+	initialized bool
+}
+
+type config struct {
+	index int
+}
+
+type surface struct {
+	display *display
+	config  int
+	window  egl.NativeWindow // nil for pbuffers
+	width   int
+	height  int
+	attribs map[int]int
+	swaps   int
+}
+
+type context struct {
+	display *display
+	config  int
+	attribs map[int]int
+	lost    bool
+}
+
+type failure struct {
+	code  egl.Error
+	count int // < 0: forever
+}
+
+// Backend is a fake EGL implementation. Set its exported fields before the
+// first call; use the methods afterwards. It is safe for concurrent use.
+type Backend struct {
+	// Configs are the configs of every display.
+	Configs []Config
+	// Extensions are the display extensions, e.g. egl.KHR_surfaceless_context.
+	// Display extensions are cached by package egl on first use.
+	Extensions []egl.Extension
+	// ClientExtensions are reported for NO_DISPLAY.
+	ClientExtensions []egl.Extension
+	// Major and Minor are the EGL version reported by Initialize.
+	Major, Minor int
+	// ESMajor and ESMinor are the highest OpenGL ES version contexts can
+	// be created with.
+	ESMajor, ESMinor int
+	// WindowWidth and WindowHeight are the size of windows not sized by
+	// Resize.
+	WindowWidth, WindowHeight int
+
+	mu       sync.Mutex
+	err      egl.Error
+	fails    map[string]*failure
+	calls    []string
+	displays map[egl.NativeDisplay]*display
+	surfaces map[egl.Surface]*surface
+	contexts map[egl.Context]*context
+	windows  map[egl.NativeWindow][2]int
+	handles  []*config
+	api      uint
+
+	curDisplay *display
+	curDraw    egl.Surface
+	curRead    egl.Surface
+	curContext egl.Context
+}
+
+// New returns a Backend with DefaultConfigs, EGL 1.4 without extensions,
+// OpenGL ES up to 3.2 and 640x480 windows.
+func New() *Backend {
+	return &Backend{
+		Configs:      DefaultConfigs(),
+		Major:        1,
+		Minor:        4,
+		ESMajor:      3,
+		ESMinor:      2,
+		WindowWidth:  640,
+		WindowHeight: 480,
+	}
+}
+
+// Install makes b the backend of package egl and returns a function that
+// restores the previous one.
+func (b *Backend) Install() (restore func()) {
+	return backend.Set(b)
+}
+
+// Fail makes the next count calls of the EGL function fn, e.g.
+// "eglSwapBuffers", fail with code. A negative count fails every call
+// until Fail is called again with count 0.
+func (b *Backend) Fail(fn string, code egl.Error, count int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.fails == nil {
+		b.fails = make(map[string]*failure)
+	}
+	if count == 0 {
+		delete(b.fails, fn)
+		return
+	}
+	b.fails[fn] = &failure{code: code, count: count}
+}
+
+// LoseContexts marks every existing context as lost, as after a GPU reset
+// or power management event: making it current or swapping with it fails
+// with CONTEXT_LOST until it is destroyed.
+func (b *Backend) LoseContexts() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range b.contexts {
+		c.lost = true
+	}
+}
+
+// Resize changes the size of the native window win, and of the window
+// surfaces created for it.
+func (b *Backend) Resize(win egl.NativeWindow, width, height int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.windows == nil {
+		b.windows = make(map[egl.NativeWindow][2]int)
+	}
+	b.windows[win] = [2]int{width, height}
+}
+
+// Calls returns the names of the EGL functions called so far.
+func (b *Backend) Calls() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.calls...)
+}
+
+// Count returns how many times the EGL function fn has been called.
+func (b *Backend) Count(fn string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for _, c := range b.calls {
+		if c == fn {
+			n++
+		}
+	}
+	return n
+}
+
+// Live returns the number of surfaces and contexts not destroyed yet, to
+// check for leaks.
+func (b *Backend) Live() (surfaces, contexts int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.surfaces), len(b.contexts)
+}
+
+// Swaps returns the number of successful SwapBuffers of s.
+func (b *Backend) Swaps(s egl.Surface) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if surf := b.surfaces[s]; surf != nil {
+		return surf.swaps
+	}
+	return 0
+}
+
+// Current returns the current context and surfaces.
+func (b *Backend) Current() (egl.Context, egl.Surface, egl.Surface) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.curContext, b.curDraw, b.curRead
+}
+
+// call records a call of fn and reports whether an injected failure
+// applies to it. b.mu must be held.
+func (b *Backend) call(fn string) bool {
+	b.calls = append(b.calls, fn)
+	f := b.fails[fn]
+	if f == nil {
+		return false
+	}
+	if f.count > 0 {
+		f.count--
+		if f.count == 0 {
+			delete(b.fails, fn)
+		}
+	}
+	b.err = f.code
+	return true
+}
+
+// fail sets the error of the current call and returns false. b.mu must
+// be held.
+func (b *Backend) fail(code egl.Error) bool {
+	b.err = code
+	return false
+}
+
+func (b *Backend) ok() bool {
+	b.err = egl.SUCCESS
+	return true
+}
+
+func (b *Backend) hasExtension(ext egl.Extension) bool {
+	for _, e := range b.Extensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Backend) core15() bool {
+	return b.Major > 1 || b.Minor >= 5
+}
+
+func (b *Backend) display(d egl.Display) *display {
+	for _, disp := range b.displays {
+		if egl.Display(unsafe.Pointer(disp)) == d {
+			return disp
+		}
+	}
+	return nil
+}
+
+// initialized returns the display d if it is initialized, and sets the
+// error otherwise. b.mu must be held.
+func (b *Backend) initialized(d egl.Display) *display {
+	disp := b.display(d)
+	switch {
+	case disp == nil:
+		b.fail(egl.BAD_DISPLAY)
+		return nil
+	case !disp.initialized:
+		b.fail(egl.NOT_INITIALIZED)
+		return nil
+	}
+	return disp
+}
+
+// config returns the index of conf, -1 if invalid.
+func (b *Backend) config(conf egl.Config) int {
+	for _, h := range b.handles {
+		if egl.Config(unsafe.Pointer(h)) == conf {
+			if h.index < len(b.Configs) {
+				return h.index
+			}
+			break
+		}
+	}
+	return -1
+}
+
+func (b *Backend) configHandle(i int) egl.Config {
+	for len(b.handles) <= i {
+		b.handles = append(b.handles, &config{index: len(b.handles)})
+	}
+	return egl.Config(unsafe.Pointer(b.handles[i]))
+}
+
+func (b *Backend) configAttrib(i, attr int) int {
+	if v, ok := b.Configs[i][attr]; ok {
+		return v
+	}
+	if attr == egl.CONFIG_ID {
+		return i + 1
+	}
+	return 0
+}
+
+// attribMap parses a NONE terminated attribute list.
+func attribMap(attribs []egl.EGLint) map[int]int {
+	m := make(map[int]int)
+	for i := 0; i+1 < len(attribs) && attribs[i] != egl.NONE; i += 2 {
+		m[int(attribs[i])] = int(attribs[i+1])
+	}
+	return m
+}
+
+func (b *Backend) GetDisplay(nd egl.NativeDisplay) egl.Display {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglGetDisplay") {
+		return egl.NO_DISPLAY
+	}
+	if b.displays == nil {
+		b.displays = make(map[egl.NativeDisplay]*display)
+	}
+	disp := b.displays[nd]
+	if disp == nil {
+		disp = &display{}
+		b.displays[nd] = disp
+	}
+	b.ok()
+	return egl.Display(unsafe.Pointer(disp))
+}
+
+func (b *Backend) Initialize(d egl.Display) (major, minor egl.EGLint, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglInitialize") {
+		return 0, 0, false
+	}
+	disp := b.display(d)
+	if disp == nil {
+		return 0, 0, b.fail(egl.BAD_DISPLAY)
+	}
+	disp.initialized = true
+	return egl.EGLint(b.Major), egl.EGLint(b.Minor), b.ok()
+}
+
+func (b *Backend) Terminate(d egl.Display) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglTerminate") {
+		return false
+	}
+	disp := b.display(d)
+	if disp == nil {
+		return b.fail(egl.BAD_DISPLAY)
+	}
+	disp.initialized = false
+	for h, s := range b.surfaces {
+		if s.display == disp {
+			delete(b.surfaces, h)
+		}
+	}
+	for h, c := range b.contexts {
+		if c.display == disp {
+			delete(b.contexts, h)
+		}
+	}
+	return b.ok()
+}
+
+func (b *Backend) QueryString(d egl.Display, name int) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglQueryString") {
+		return "", false
+	}
+	if d == egl.NO_DISPLAY {
+		switch {
+		case name == egl.EXTENSIONS:
+			return joinExtensions(b.ClientExtensions), b.ok()
+		case name == egl.VERSION && b.core15():
+			return b.version(), b.ok()
+		}
+		return "", b.fail(egl.BAD_DISPLAY)
+	}
+	if b.initialized(d) == nil {
+		return "", false
+	}
+	switch name {
+	case egl.VENDOR:
+		return "egltest", b.ok()
+	case egl.VERSION:
+		return b.version(), b.ok()
+	case egl.EXTENSIONS:
+		return joinExtensions(b.Extensions), b.ok()
+	case egl.CLIENT_APIS:
+		return "OpenGL_ES", b.ok()
+	}
+	return "", b.fail(egl.BAD_PARAMETER)
+}
+
+func (b *Backend) version() string {
+	return fmt.Sprintf("%d.%d egltest", b.Major, b.Minor)
+}
+
+func joinExtensions(list []egl.Extension) string {
+	s := make([]string, len(list))
+	for i, e := range list {
+		s[i] = string(e)
+	}
+	return strings.Join(s, " ")
+}
+
+func (b *Backend) GetConfigs(d egl.Display, confs []egl.Config) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglGetConfigs") {
+		return 0, false
+	}
+	if b.initialized(d) == nil {
+		return 0, false
+	}
+	if confs == nil {
+		return len(b.Configs), b.ok()
+	}
+	n := 0
+	for ; n < len(confs) && n < len(b.Configs); n++ {
+		confs[n] = b.configHandle(n)
+	}
+	return n, b.ok()
+}
+
+// ChooseConfig matches sizes as minimums, SURFACE_TYPE, RENDERABLE_TYPE
+// and CONFORMANT as bitmasks and other attributes exactly, and returns
+// the matches in the order of Configs.
+func (b *Backend) ChooseConfig(d egl.Display, attribs []egl.EGLint, confs []egl.Config) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglChooseConfig") {
+		return 0, false
+	}
+	if b.initialized(d) == nil {
+		return 0, false
+	}
+	want := attribMap(attribs)
+	n := 0
+	for i := range b.Configs {
+		if !b.matches(i, want) {
+			continue
+		}
+		if confs != nil {
+			if n == len(confs) {
+				break
+			}
+			confs[n] = b.configHandle(i)
+		}
+		n++
+	}
+	return n, b.ok()
+}
+
+func (b *Backend) matches(i int, want map[int]int) bool {
+	for attr, v := range want {
+		if v == egl.DONT_CARE {
+			continue
+		}
+		have := b.configAttrib(i, attr)
+		switch attr {
+		case egl.SURFACE_TYPE, egl.RENDERABLE_TYPE, egl.CONFORMANT:
+			if have&v != v {
+				return false
+			}
+		case egl.BUFFER_SIZE, egl.RED_SIZE, egl.GREEN_SIZE, egl.BLUE_SIZE,
+			egl.ALPHA_SIZE, egl.DEPTH_SIZE, egl.STENCIL_SIZE,
+			egl.SAMPLES, egl.SAMPLE_BUFFERS:
+			if have < v {
+				return false
+			}
+		default:
+			if have != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (b *Backend) GetConfigAttrib(d egl.Display, conf egl.Config, attr int) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglGetConfigAttrib") {
+		return 0, false
+	}
+	if b.initialized(d) == nil {
+		return 0, false
+	}
+	i := b.config(conf)
+	if i < 0 {
+		return 0, b.fail(egl.BAD_CONFIG)
+	}
+	return b.configAttrib(i, attr), b.ok()
+}
+
+// newSurface checks the arguments common to all surface types. b.mu must
+// be held.
+func (b *Backend) newSurface(d egl.Display, conf egl.Config, bit int, attribs []egl.EGLint) *surface {
+	disp := b.initialized(d)
+	if disp == nil {
+		return nil
+	}
+	i := b.config(conf)
+	if i < 0 {
+		b.fail(egl.BAD_CONFIG)
+		return nil
+	}
+	if b.configAttrib(i, egl.SURFACE_TYPE)&bit == 0 {
+		b.fail(egl.BAD_MATCH)
+		return nil
+	}
+	s := &surface{display: disp, config: i, attribs: attribMap(attribs)}
+	if cs, ok := s.attribs[egl.GL_COLORSPACE]; ok && !b.supportsColorspace(egl.Colorspace(cs)) {
+		b.fail(egl.BAD_ATTRIBUTE)
+		return nil
+	}
+	return s
+}
+
+func (b *Backend) supportsColorspace(cs egl.Colorspace) bool {
+	switch cs {
+	case egl.GL_COLORSPACE_SRGB, egl.GL_COLORSPACE_LINEAR:
+		return b.core15() || b.hasExtension(egl.KHR_gl_colorspace)
+	}
+	return false
+}
+
+func (b *Backend) addSurface(s *surface) egl.Surface {
+	if b.surfaces == nil {
+		b.surfaces = make(map[egl.Surface]*surface)
+	}
+	h := egl.Surface(unsafe.Pointer(s))
+	b.surfaces[h] = s
+	b.ok()
+	return h
+}
+
+func (b *Backend) CreateWindowSurface(d egl.Display, conf egl.Config, win egl.NativeWindow, attribs []egl.EGLint) egl.Surface {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglCreateWindowSurface") {
+		return egl.NO_SURFACE
+	}
+	s := b.newSurface(d, conf, egl.WINDOW_BIT, attribs)
+	if s == nil {
+		return egl.NO_SURFACE
+	}
+	if win == nil {
+		b.fail(egl.BAD_NATIVE_WINDOW)
+		return egl.NO_SURFACE
+	}
+	s.window = win
+	return b.addSurface(s)
+}
+
+func (b *Backend) CreatePbufferSurface(d egl.Display, conf egl.Config, attribs []egl.EGLint) egl.Surface {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglCreatePbufferSurface") {
+		return egl.NO_SURFACE
+	}
+	s := b.newSurface(d, conf, egl.PBUFFER_BIT, attribs)
+	if s == nil {
+		return egl.NO_SURFACE
+	}
+	s.width, s.height = s.attribs[egl.WIDTH], s.attribs[egl.HEIGHT]
+	return b.addSurface(s)
+}
+
+// CreatePixmapSurface always fails: the fake has no native pixmaps.
+func (b *Backend) CreatePixmapSurface(d egl.Display, conf egl.Config, pixmap egl.NativePixmap, attribs []egl.EGLint) egl.Surface {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglCreatePixmapSurface") {
+		return egl.NO_SURFACE
+	}
+	b.fail(egl.BAD_NATIVE_PIXMAP)
+	return egl.NO_SURFACE
+}
+
+// CreatePbufferFromClientBuffer always fails: the fake has no client
+// buffers.
+func (b *Backend) CreatePbufferFromClientBuffer(d egl.Display, buftyp uint, conf egl.Config, buf egl.ClientBuffer, attribs []egl.EGLint) egl.Surface {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglCreatePbufferFromClientBuffer") {
+		return egl.NO_SURFACE
+	}
+	b.fail(egl.BAD_PARAMETER)
+	return egl.NO_SURFACE
+}
+
+// surface returns the surface s if it is valid, and sets the error
+// otherwise. b.mu must be held.
+func (b *Backend) surface(d egl.Display, s egl.Surface) *surface {
+	if b.initialized(d) == nil {
+		return nil
+	}
+	surf := b.surfaces[s]
+	if surf == nil {
+		b.fail(egl.BAD_SURFACE)
+	}
+	return surf
+}
+
+func (b *Backend) DestroySurface(d egl.Display, s egl.Surface) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglDestroySurface") {
+		return false
+	}
+	if b.surface(d, s) == nil {
+		return false
+	}
+	delete(b.surfaces, s)
+	return b.ok()
+}
+
+func (b *Backend) size(s *surface) (int, int) {
+	if s.window == nil {
+		return s.width, s.height
+	}
+	if size, ok := b.windows[s.window]; ok {
+		return size[0], size[1]
+	}
+	return b.WindowWidth, b.WindowHeight
+}
+
+func (b *Backend) QuerySurface(d egl.Display, s egl.Surface, attr int) (egl.EGLint, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglQuerySurface") {
+		return 0, false
+	}
+	surf := b.surface(d, s)
+	if surf == nil {
+		return 0, false
+	}
+	w, h := b.size(surf)
+	switch attr {
+	case egl.WIDTH:
+		return egl.EGLint(w), b.ok()
+	case egl.HEIGHT:
+		return egl.EGLint(h), b.ok()
+	case egl.CONFIG_ID:
+		return egl.EGLint(b.configAttrib(surf.config, egl.CONFIG_ID)), b.ok()
+	case egl.RENDER_BUFFER:
+		return egl.BACK_BUFFER, b.ok()
+	}
+	if v, ok := surf.attribs[attr]; ok {
+		return egl.EGLint(v), b.ok()
+	}
+	if attr == egl.GL_COLORSPACE && b.supportsColorspace(egl.GL_COLORSPACE_LINEAR) {
+		return egl.EGLint(egl.GL_COLORSPACE_LINEAR), b.ok()
+	}
+	return 0, b.fail(egl.BAD_ATTRIBUTE)
+}
+
+func (b *Backend) SurfaceAttrib(d egl.Display, s egl.Surface, attr int, val int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglSurfaceAttrib") {
+		return false
+	}
+	surf := b.surface(d, s)
+	if surf == nil {
+		return false
+	}
+	surf.attribs[attr] = val
+	return b.ok()
+}
+
+func (b *Backend) BindTexImage(d egl.Display, s egl.Surface, buf int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglBindTexImage") {
+		return false
+	}
+	return b.surface(d, s) != nil && b.ok()
+}
+
+func (b *Backend) ReleaseTexImage(d egl.Display, s egl.Surface, buf int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglReleaseTexImage") {
+		return false
+	}
+	return b.surface(d, s) != nil && b.ok()
+}
+
+func (b *Backend) SwapInterval(d egl.Display, inv int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglSwapInterval") {
+		return false
+	}
+	if b.initialized(d) == nil {
+		return false
+	}
+	if b.curContext == egl.NO_CONTEXT {
+		return b.fail(egl.BAD_CONTEXT)
+	}
+	if b.curDraw == egl.NO_SURFACE {
+		return b.fail(egl.BAD_SURFACE)
+	}
+	b.surfaces[b.curDraw].attribs[egl.MIN_SWAP_INTERVAL] = inv
+	return b.ok()
+}
+
+// contextAttribute tells whether CreateContext accepts attr.
+func (b *Backend) contextAttribute(attr int) bool {
+	switch attr {
+	case egl.CONTEXT_CLIENT_VERSION:
+		return true
+	case egl.CONTEXT_MINOR_VERSION, egl.CONTEXT_OPENGL_DEBUG:
+		return b.core15() || attr == egl.CONTEXT_MINOR_VERSION && b.hasExtension(egl.KHR_create_context)
+	case egl.CONTEXT_FLAGS_KHR:
+		return b.hasExtension(egl.KHR_create_context)
+	case egl.CONTEXT_OPENGL_ROBUST_ACCESS_EXT, egl.CONTEXT_OPENGL_RESET_NOTIFICATION_STRATEGY_EXT:
+		return b.hasExtension(egl.EXT_create_context_robustness)
+	case egl.CONTEXT_OPENGL_NO_ERROR_KHR:
+		return b.hasExtension(egl.KHR_create_context_no_error)
+	case egl.CONTEXT_PRIORITY_LEVEL_IMG:
+		return b.hasExtension(egl.IMG_context_priority)
+	}
+	return false
+}
+
+func (b *Backend) CreateContext(d egl.Display, conf egl.Config, shared egl.Context, attribs []egl.EGLint) egl.Context {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglCreateContext") {
+		return egl.NO_CONTEXT
+	}
+	disp := b.initialized(d)
+	if disp == nil {
+		return egl.NO_CONTEXT
+	}
+	i := b.config(conf)
+	if i < 0 {
+		b.fail(egl.BAD_CONFIG)
+		return egl.NO_CONTEXT
+	}
+	if shared != egl.NO_CONTEXT && b.contexts[shared] == nil {
+		b.fail(egl.BAD_CONTEXT)
+		return egl.NO_CONTEXT
+	}
+	m := attribMap(attribs)
+	for attr := range m {
+		if !b.contextAttribute(attr) {
+			b.fail(egl.BAD_ATTRIBUTE)
+			return egl.NO_CONTEXT
+		}
+	}
+	major, ok := m[egl.CONTEXT_CLIENT_VERSION]
+	if !ok {
+		major = 1
+		m[egl.CONTEXT_CLIENT_VERSION] = 1
+	}
+	minor := m[egl.CONTEXT_MINOR_VERSION]
+	if major > b.ESMajor || major == b.ESMajor && minor > b.ESMinor {
+		b.fail(egl.BAD_MATCH)
+		return egl.NO_CONTEXT
+	}
+	bit := egl.OPENGL_ES2_BIT
+	if major >= 3 {
+		bit = egl.OPENGL_ES3_BIT
+	}
+	if major >= 2 && b.configAttrib(i, egl.RENDERABLE_TYPE)&bit == 0 {
+		b.fail(egl.BAD_MATCH)
+		return egl.NO_CONTEXT
+	}
+	if _, ok := m[egl.CONTEXT_PRIORITY_LEVEL_IMG]; !ok {
+		m[egl.CONTEXT_PRIORITY_LEVEL_IMG] = egl.CONTEXT_PRIORITY_MEDIUM_IMG
+	}
+	c := &context{display: disp, config: i, attribs: m}
+	if b.contexts == nil {
+		b.contexts = make(map[egl.Context]*context)
+	}
+	h := egl.Context(unsafe.Pointer(c))
+	b.contexts[h] = c
+	b.ok()
+	return h
+}
+
+func (b *Backend) DestroyContext(d egl.Display, c egl.Context) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglDestroyContext") {
+		return false
+	}
+	if b.initialized(d) == nil {
+		return false
+	}
+	if b.contexts[c] == nil {
+		return b.fail(egl.BAD_CONTEXT)
+	}
+	delete(b.contexts, c)
+	return b.ok()
+}
+
+func (b *Backend) MakeCurrent(d egl.Display, draw egl.Surface, read egl.Surface, c egl.Context) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglMakeCurrent") {
+		return false
+	}
+	if c == egl.NO_CONTEXT {
+		if draw != egl.NO_SURFACE || read != egl.NO_SURFACE {
+			return b.fail(egl.BAD_MATCH)
+		}
+		if b.display(d) == nil {
+			return b.fail(egl.BAD_DISPLAY)
+		}
+		b.release()
+		return b.ok()
+	}
+	disp := b.initialized(d)
+	if disp == nil {
+		return false
+	}
+	ctx := b.contexts[c]
+	switch {
+	case ctx == nil:
+		return b.fail(egl.BAD_CONTEXT)
+	case ctx.lost:
+		return b.fail(egl.CONTEXT_LOST)
+	}
+	for _, s := range []egl.Surface{draw, read} {
+		if s == egl.NO_SURFACE {
+			if !b.hasExtension(egl.KHR_surfaceless_context) {
+				return b.fail(egl.BAD_MATCH)
+			}
+		} else if b.surfaces[s] == nil {
+			return b.fail(egl.BAD_SURFACE)
+		}
+	}
+	b.curDisplay, b.curDraw, b.curRead, b.curContext = disp, draw, read, c
+	return b.ok()
+}
+
+func (b *Backend) release() {
+	b.curDisplay, b.curDraw, b.curRead, b.curContext = nil, egl.NO_SURFACE, egl.NO_SURFACE, egl.NO_CONTEXT
+}
+
+func (b *Backend) QueryContext(d egl.Display, c egl.Context, attr int) (egl.EGLint, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglQueryContext") {
+		return 0, false
+	}
+	if b.initialized(d) == nil {
+		return 0, false
+	}
+	ctx := b.contexts[c]
+	if ctx == nil {
+		return 0, b.fail(egl.BAD_CONTEXT)
+	}
+	switch attr {
+	case egl.CONFIG_ID:
+		return egl.EGLint(b.configAttrib(ctx.config, egl.CONFIG_ID)), b.ok()
+	case egl.CONTEXT_CLIENT_TYPE:
+		return egl.OPENGL_ES_API, b.ok()
+	case egl.RENDER_BUFFER:
+		return egl.BACK_BUFFER, b.ok()
+	}
+	if v, ok := ctx.attribs[attr]; ok {
+		return egl.EGLint(v), b.ok()
+	}
+	return 0, b.fail(egl.BAD_ATTRIBUTE)
+}
+
+func (b *Backend) GetCurrentSurface(readdraw int) egl.Surface {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.call("eglGetCurrentSurface")
+	if readdraw == egl.READ {
+		return b.curRead
+	}
+	return b.curDraw
+}
+
+func (b *Backend) GetCurrentDisplay() egl.Display {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.call("eglGetCurrentDisplay")
+	if b.curDisplay == nil {
+		return egl.NO_DISPLAY
+	}
+	return egl.Display(unsafe.Pointer(b.curDisplay))
+}
+
+// CopyBuffers always fails: the fake has no native pixmaps.
+func (b *Backend) CopyBuffers(d egl.Display, s egl.Surface, target egl.NativePixmap) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglCopyBuffers") {
+		return false
+	}
+	if b.surface(d, s) == nil {
+		return false
+	}
+	return b.fail(egl.BAD_NATIVE_PIXMAP)
+}
+
+func (b *Backend) SwapBuffers(d egl.Display, s egl.Surface) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglSwapBuffers") {
+		return false
+	}
+	surf := b.surface(d, s)
+	if surf == nil {
+		return false
+	}
+	if b.curContext == egl.NO_CONTEXT || b.curDraw != s {
+		return b.fail(egl.BAD_SURFACE)
+	}
+	if b.contexts[b.curContext] == nil {
+		return b.fail(egl.BAD_CONTEXT)
+	}
+	if b.contexts[b.curContext].lost {
+		return b.fail(egl.CONTEXT_LOST)
+	}
+	surf.swaps++
+	return b.ok()
+}
+
+func (b *Backend) BindAPI(api uint) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglBindAPI") {
+		return false
+	}
+	if api != egl.OPENGL_ES_API {
+		return b.fail(egl.BAD_PARAMETER)
+	}
+	b.api = api
+	return b.ok()
+}
+
+func (b *Backend) QueryAPI() uint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.call("eglQueryAPI")
+	return egl.OPENGL_ES_API
+}
+
+func (b *Backend) WaitNative(engine int) bool { return b.succeed("eglWaitNative") }
+func (b *Backend) WaitClient() bool           { return b.succeed("eglWaitClient") }
+func (b *Backend) WaitGL() bool               { return b.succeed("eglWaitGL") }
+
+func (b *Backend) succeed(fn string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call(fn) {
+		return false
+	}
+	return b.ok()
+}
+
+func (b *Backend) ReleaseThread() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.call("eglReleaseThread") {
+		return false
+	}
+	b.release()
+	return b.ok()
+}
+
+// GetProcAddress returns nil: the fake has no extension entry points.
+func (b *Backend) GetProcAddress(name string) unsafe.Pointer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.call("eglGetProcAddress")
+	return nil
+}
+
+// GetError returns the error of the last call and resets it to SUCCESS.
+func (b *Backend) GetError() egl.Error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, "eglGetError")
+	err := b.err
+	b.err = egl.SUCCESS
+	return err
+}
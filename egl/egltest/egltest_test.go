@@ -0,0 +1,73 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egltest_test
+
+import (
+	"testing"
+
+	"github.com/gooid/gl/egl"
+	"github.com/gooid/gl/egl/egltest"
+)
+
+func TestFail(t *testing.T) {
+	b := egltest.New()
+	defer b.Install()()
+
+	d := egl.GetDisplay(egl.DEFAULT_DISPLAY)
+	b.Fail("eglInitialize", egl.NOT_INITIALIZED, 2)
+	for i := 0; i < 2; i++ {
+		if egl.Initialize(d) {
+			t.Fatalf("Initialize %d succeeded, want an injected failure", i)
+		}
+		if code := egl.GetError(); code != egl.NOT_INITIALIZED {
+			t.Errorf("GetError() = %v, want NOT_INITIALIZED", code)
+		}
+	}
+	if !egl.Initialize(d) {
+		t.Fatal("Initialize failed after the injected failures")
+	}
+	if code := egl.GetError(); code != egl.SUCCESS {
+		t.Errorf("GetError() = %v, want SUCCESS", code)
+	}
+	if n := b.Count("eglInitialize"); n != 3 {
+		t.Errorf("Count(eglInitialize) = %d, want 3", n)
+	}
+}
+
+func TestLoseContexts(t *testing.T) {
+	b := egltest.New()
+	defer b.Install()()
+
+	d := egl.GetDisplay(egl.DEFAULT_DISPLAY)
+	if !egl.Initialize(d) {
+		t.Fatal("Initialize failed")
+	}
+	var confs [1]egl.Config
+	if egl.ChooseConfig(d, []egl.EGLint{egl.RENDERABLE_TYPE, egl.OPENGL_ES2_BIT, egl.NONE}, confs[:]) != 1 {
+		t.Fatal("no config")
+	}
+	s := egl.CreatePbufferSurface(d, confs[0], []egl.EGLint{egl.WIDTH, 4, egl.HEIGHT, 4, egl.NONE})
+	c := egl.CreateContext(d, confs[0], egl.NO_CONTEXT, []egl.EGLint{egl.CONTEXT_CLIENT_VERSION, 2, egl.NONE})
+	if s == egl.NO_SURFACE || c == egl.NO_CONTEXT {
+		t.Fatalf("create: %v", egl.GetError())
+	}
+	if !egl.MakeCurrent(d, s, s, c) {
+		t.Fatalf("MakeCurrent: %v", egl.GetError())
+	}
+
+	b.LoseContexts()
+	if egl.SwapBuffers(d, s) || egl.GetError() != egl.CONTEXT_LOST {
+		t.Error("SwapBuffers of a lost context did not fail with CONTEXT_LOST")
+	}
+	if egl.MakeCurrent(d, s, s, c) || egl.GetError() != egl.CONTEXT_LOST {
+		t.Error("MakeCurrent of a lost context did not fail with CONTEXT_LOST")
+	}
+
+	egl.MakeCurrent(d, egl.NO_SURFACE, egl.NO_SURFACE, egl.NO_CONTEXT)
+	egl.Terminate(d)
+	if s, c := b.Live(); s != 0 || c != 0 {
+		t.Errorf("live surfaces, contexts = %d, %d after Terminate", s, c)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+/*
+#include <EGL/egl.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"unsafe"
+)
+
+// CallError describes a failed EGL call: the entry point, the arguments
+// it was called with and the code reported by eglGetError.
+type CallError struct {
+	Func string
+	Args []interface{}
+	Code Error
+}
+
+func (e *CallError) Error() string {
+	args := make([]string, len(e.Args))
+	for i, a := range e.Args {
+		args[i] = fmt.Sprint(a)
+	}
+	return fmt.Sprintf("%s(%s) failed: %v", e.Func, strings.Join(args, ", "), e.Code)
+}
+
+// Unwrap returns the EGL error code, so that errors.Is(err, BAD_SURFACE)
+// and errors.As(err, &code) work on a *CallError.
+func (e *CallError) Unwrap() error {
+	return e.Code
+}
+
+// ErrorCode returns the EGL error code carried by err, or SUCCESS if err
+// does not wrap an Error.
+func ErrorCode(err error) Error {
+	var code Error
+	if errors.As(err, &code) {
+		return code
+	}
+	return SUCCESS
+}
+
+// check turns the result of an EGL call into an error. It must run on
+// the OS thread that made the call, see lockThread.
+func check(ok bool, fn string, args ...interface{}) error {
+	if ok {
+		return nil
+	}
+	return &CallError{Func: fn, Args: args, Code: GetError()}
+}
+
+// lockThread pins the calling goroutine to its OS thread for the
+// duration of an EGL call and the GetError that follows it. Calls nest,
+// so callers that already locked their thread keep it locked.
+func lockThread() func() {
+	runtime.LockOSThread()
+	return runtime.UnlockOSThread
+}
+
+func InitializeErr(d Display) error {
+	defer lockThread()()
+	return check(Initialize(d), "eglInitialize", d)
+}
+func TerminateErr(d Display) error {
+	defer lockThread()()
+	return check(Terminate(d), "eglTerminate", d)
+}
+func GetDisplayErr(nd NativeDisplay) (Display, error) {
+	defer lockThread()()
+	d := GetDisplay(nd)
+	return d, check(d != NO_DISPLAY, "eglGetDisplay", nd)
+}
+func QueryStringErr(d Display, name int) (string, error) {
+	defer lockThread()()
+	s, ok := be().QueryString(d, name)
+	return s, check(ok, "eglQueryString", d, name)
+}
+func DestroySurfaceErr(d Display, s Surface) error {
+	defer lockThread()()
+	return check(DestroySurface(d, s), "eglDestroySurface", d, s)
+}
+func SwapIntervalErr(d Display, inv int) error {
+	defer lockThread()()
+	return check(SwapInterval(d, inv), "eglSwapInterval", d, inv)
+}
+func DestroyContextErr(d Display, c Context) error {
+	defer lockThread()()
+	return check(DestroyContext(d, c), "eglDestroyContext", d, c)
+}
+func QuerySurfaceErr(d Display, s Surface, attr int) (EGLint, error) {
+	defer lockThread()()
+	val, ok := QuerySurface(d, s, attr)
+	return val, check(ok, "eglQuerySurface", d, s, attr)
+}
+func GetConfigsErr(d Display, confs []Config) (int, error) {
+	defer lockThread()()
+	n, ok := be().GetConfigs(d, confs)
+	return n, check(ok, "eglGetConfigs", d, len(confs))
+}
+func GetConfigAttribErr(d Display, conf Config, attr int) (int, error) {
+	defer lockThread()()
+	val, ok := GetConfigAttrib(d, conf, attr)
+	return val, check(ok, "eglGetConfigAttrib", d, conf, attr)
+}
+func ChooseConfigErr(d Display, atrribs []EGLint, confs []Config) (int, error) {
+	defer lockThread()()
+	n, ok := be().ChooseConfig(d, atrribs, confs)
+	return n, check(ok, "eglChooseConfig", d, atrribs)
+}
+func CreateContextErr(d Display, conf Config, shared Context, attribs []EGLint) (Context, error) {
+	defer lockThread()()
+	c := CreateContext(d, conf, shared, attribs)
+	return c, check(c != NO_CONTEXT, "eglCreateContext", d, conf, shared, attribs)
+}
+func CreateWindowSurfaceErr(d Display, conf Config, win NativeWindow, attribs []EGLint) (Surface, error) {
+	defer lockThread()()
+	s := CreateWindowSurface(d, conf, win, attribs)
+	return s, check(s != NO_SURFACE, "eglCreateWindowSurface", d, conf, win, attribs)
+}
+func CreatePbufferSurfaceErr(d Display, conf Config, attribs []EGLint) (Surface, error) {
+	defer lockThread()()
+	s := CreatePbufferSurface(d, conf, attribs)
+	return s, check(s != NO_SURFACE, "eglCreatePbufferSurface", d, conf, attribs)
+}
+func CreatePixmapSurfaceErr(d Display, conf Config, pixmap NativePixmap, attribs []EGLint) (Surface, error) {
+	defer lockThread()()
+	s := CreatePixmapSurface(d, conf, pixmap, attribs)
+	return s, check(s != NO_SURFACE, "eglCreatePixmapSurface", d, conf, pixmap, attribs)
+}
+func CreatePbufferFromClientBufferErr(
+	d Display, buftyp uint, conf Config, buf ClientBuffer, attribs []EGLint) (Surface, error) {
+	defer lockThread()()
+	s := CreatePbufferFromClientBuffer(d, buftyp, conf, buf, attribs)
+	return s, check(s != NO_SURFACE, "eglCreatePbufferFromClientBuffer", d, buftyp, conf, buf, attribs)
+}
+func SurfaceAttribErr(d Display, s Surface, attr int, val int) error {
+	defer lockThread()()
+	return check(SurfaceAttrib(d, s, attr, val), "eglSurfaceAttrib", d, s, attr, val)
+}
+func BindTexImageErr(d Display, s Surface, buf int) error {
+	defer lockThread()()
+	return check(BindTexImage(d, s, buf), "eglBindTexImage", d, s, buf)
+}
+func ReleaseTexImageErr(d Display, s Surface, buf int) error {
+	defer lockThread()()
+	return check(ReleaseTexImage(d, s, buf), "eglReleaseTexImage", d, s, buf)
+}
+func MakeCurrentErr(d Display, draw Surface, read Surface, c Context) error {
+	defer lockThread()()
+	return check(MakeCurrent(d, draw, read, c), "eglMakeCurrent", d, draw, read, c)
+}
+func QueryContextErr(d Display, c Context, attr int) (EGLint, error) {
+	defer lockThread()()
+	var val [1]EGLint
+	ok := QueryContext(d, c, attr, val[:])
+	return val[0], check(ok, "eglQueryContext", d, c, attr)
+}
+func CopyBuffersErr(d Display, s Surface, target NativePixmap) error {
+	defer lockThread()()
+	return check(CopyBuffers(d, s, target), "eglCopyBuffers", d, s, target)
+}
+func SwapBuffersErr(d Display, s Surface) error {
+	defer lockThread()()
+	return check(SwapBuffers(d, s), "eglSwapBuffers", d, s)
+}
+
+func BindAPIErr(api uint) error {
+	defer lockThread()()
+	return check(BindAPI(api), "eglBindAPI", api)
+}
+func WaitNativeErr(engine int) error {
+	defer lockThread()()
+	return check(WaitNative(engine), "eglWaitNative", engine)
+}
+func WaitClientErr() error {
+	defer lockThread()()
+	return check(WaitClient(), "eglWaitClient")
+}
+func WaitGLErr() error {
+	defer lockThread()()
+	return check(WaitGL(), "eglWaitGL")
+}
+func ReleaseThreadErr() error {
+	defer lockThread()()
+	return check(ReleaseThread(), "eglReleaseThread")
+}
+
+// attribList returns a pointer to the first element of an attribute
+// list, or nil for an empty one (which EGL treats as an empty list).
+func attribList(attribs []EGLint) *C.EGLint {
+	if len(attribs) == 0 {
+		return nil
+	}
+	return (*C.EGLint)(unsafe.Pointer(&attribs[0]))
+}
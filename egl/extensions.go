@@ -0,0 +1,221 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Extension is the name of an EGL extension.
+type Extension string
+
+// Extensions the egl package knows about.
+const (
+	KHR_create_context                Extension = "EGL_KHR_create_context"
+	KHR_create_context_no_error       Extension = "EGL_KHR_create_context_no_error"
+	KHR_get_all_proc_addresses        Extension = "EGL_KHR_get_all_proc_addresses"
+	KHR_client_get_all_proc_addresses Extension = "EGL_KHR_client_get_all_proc_addresses"
+	KHR_surfaceless_context           Extension = "EGL_KHR_surfaceless_context"
+	KHR_no_config_context             Extension = "EGL_KHR_no_config_context"
+	KHR_gl_colorspace                 Extension = "EGL_KHR_gl_colorspace"
+	KHR_image                         Extension = "EGL_KHR_image"
+	KHR_image_base                    Extension = "EGL_KHR_image_base"
+	KHR_gl_texture_2D_image           Extension = "EGL_KHR_gl_texture_2D_image"
+	KHR_fence_sync                    Extension = "EGL_KHR_fence_sync"
+	KHR_reusable_sync                 Extension = "EGL_KHR_reusable_sync"
+	KHR_wait_sync                     Extension = "EGL_KHR_wait_sync"
+	KHR_swap_buffers_with_damage      Extension = "EGL_KHR_swap_buffers_with_damage"
+	KHR_platform_gbm                  Extension = "EGL_KHR_platform_gbm"
+	KHR_platform_wayland              Extension = "EGL_KHR_platform_wayland"
+	KHR_platform_x11                  Extension = "EGL_KHR_platform_x11"
+	EXT_buffer_age                    Extension = "EGL_EXT_buffer_age"
+	EXT_swap_buffers_with_damage      Extension = "EGL_EXT_swap_buffers_with_damage"
+	EXT_create_context_robustness     Extension = "EGL_EXT_create_context_robustness"
+	EXT_image_dma_buf_import          Extension = "EGL_EXT_image_dma_buf_import"
+	EXT_platform_base                 Extension = "EGL_EXT_platform_base"
+	EXT_platform_device               Extension = "EGL_EXT_platform_device"
+	EXT_device_base                   Extension = "EGL_EXT_device_base"
+	EXT_device_enumeration            Extension = "EGL_EXT_device_enumeration"
+	EXT_device_query                  Extension = "EGL_EXT_device_query"
+	EXT_device_drm                    Extension = "EGL_EXT_device_drm"
+	EXT_device_drm_render_node        Extension = "EGL_EXT_device_drm_render_node"
+	MESA_platform_gbm                 Extension = "EGL_MESA_platform_gbm"
+	MESA_platform_surfaceless         Extension = "EGL_MESA_platform_surfaceless"
+	IMG_context_priority              Extension = "EGL_IMG_context_priority"
+
+	EXT_gl_colorspace_display_p3             Extension = "EGL_EXT_gl_colorspace_display_p3"
+	EXT_gl_colorspace_display_p3_linear      Extension = "EGL_EXT_gl_colorspace_display_p3_linear"
+	EXT_gl_colorspace_display_p3_passthrough Extension = "EGL_EXT_gl_colorspace_display_p3_passthrough"
+	EXT_gl_colorspace_scrgb                  Extension = "EGL_EXT_gl_colorspace_scrgb"
+	EXT_gl_colorspace_scrgb_linear           Extension = "EGL_EXT_gl_colorspace_scrgb_linear"
+	EXT_gl_colorspace_bt2020_pq              Extension = "EGL_EXT_gl_colorspace_bt2020_pq"
+	EXT_gl_colorspace_bt2020_linear          Extension = "EGL_EXT_gl_colorspace_bt2020_linear"
+)
+
+var knownExtensions = []Extension{
+	KHR_create_context,
+	KHR_create_context_no_error,
+	KHR_get_all_proc_addresses,
+	KHR_client_get_all_proc_addresses,
+	KHR_surfaceless_context,
+	KHR_no_config_context,
+	KHR_gl_colorspace,
+	KHR_image,
+	KHR_image_base,
+	KHR_gl_texture_2D_image,
+	KHR_fence_sync,
+	KHR_reusable_sync,
+	KHR_wait_sync,
+	KHR_swap_buffers_with_damage,
+	KHR_platform_gbm,
+	KHR_platform_wayland,
+	KHR_platform_x11,
+	EXT_buffer_age,
+	EXT_swap_buffers_with_damage,
+	EXT_create_context_robustness,
+	EXT_image_dma_buf_import,
+	EXT_platform_base,
+	EXT_platform_device,
+	EXT_device_base,
+	EXT_device_enumeration,
+	EXT_device_query,
+	EXT_device_drm,
+	EXT_device_drm_render_node,
+	MESA_platform_gbm,
+	MESA_platform_surfaceless,
+	IMG_context_priority,
+	EXT_gl_colorspace_display_p3,
+	EXT_gl_colorspace_display_p3_linear,
+	EXT_gl_colorspace_display_p3_passthrough,
+	EXT_gl_colorspace_scrgb,
+	EXT_gl_colorspace_scrgb_linear,
+	EXT_gl_colorspace_bt2020_pq,
+	EXT_gl_colorspace_bt2020_linear,
+}
+
+// ExtensionSet is a parsed EXTENSIONS string.
+type ExtensionSet struct {
+	names []string
+	set   map[string]bool
+}
+
+func newExtensionSet(s string) *ExtensionSet {
+	es := &ExtensionSet{names: strings.Fields(s), set: make(map[string]bool)}
+	for _, name := range es.names {
+		es.set[name] = true
+	}
+	sort.Strings(es.names)
+	return es
+}
+
+// Has reports whether the extension is in the set.
+func (es *ExtensionSet) Has(name Extension) bool {
+	return es != nil && es.set[string(name)]
+}
+
+// List returns all extension names in the set, sorted.
+func (es *ExtensionSet) List() []string {
+	if es == nil {
+		return nil
+	}
+	return append([]string(nil), es.names...)
+}
+
+// Known returns the extensions of the set that have a constant in this
+// package.
+func (es *ExtensionSet) Known() []Extension {
+	var known []Extension
+	for _, ext := range knownExtensions {
+		if es.Has(ext) {
+			known = append(known, ext)
+		}
+	}
+	return known
+}
+
+func (es *ExtensionSet) String() string {
+	return strings.Join(es.List(), " ")
+}
+
+// displayCache holds what is known about each display: its extensions
+// and the EGL version it was initialized with.
+var displayCache struct {
+	sync.Mutex
+	sets     map[Display]*ExtensionSet
+	versions map[Display][2]int
+}
+
+// DisplayExtensions returns the extensions of an initialized display.
+// The result is cached per display. Passing NO_DISPLAY returns the client
+// extensions.
+func DisplayExtensions(d Display) *ExtensionSet {
+	displayCache.Lock()
+	defer displayCache.Unlock()
+	if es, ok := displayCache.sets[d]; ok {
+		return es
+	}
+	s, err := QueryStringErr(d, EXTENSIONS)
+	es := newExtensionSet(s)
+	if err != nil {
+		// not initialized yet, or no client extensions: don't cache
+		return es
+	}
+	if displayCache.sets == nil {
+		displayCache.sets = make(map[Display]*ExtensionSet)
+	}
+	displayCache.sets[d] = es
+	return es
+}
+
+// DisplayVersion returns the EGL version of an initialized display. The
+// version differs between displays, e.g. of different platforms, so
+// prefer it to the package Version of the last Initialize.
+func DisplayVersion(d Display) (major, minor int) {
+	displayCache.Lock()
+	v, ok := displayCache.versions[d]
+	displayCache.Unlock()
+	if ok {
+		return v[0], v[1]
+	}
+	// initialized behind the package's back
+	major, minor, _ = parseVersion(QueryString(d, VERSION))
+	return major, minor
+}
+
+// flushDisplayCache forgets everything known about displays.
+func flushDisplayCache() {
+	displayCache.Lock()
+	defer displayCache.Unlock()
+	displayCache.sets = nil
+	displayCache.versions = nil
+}
+
+func setDisplayVersion(d Display, major, minor int) {
+	displayCache.Lock()
+	defer displayCache.Unlock()
+	if displayCache.versions == nil {
+		displayCache.versions = make(map[Display][2]int)
+	}
+	displayCache.versions[d] = [2]int{major, minor}
+}
+
+// isCore15 tells whether display d implements EGL 1.5.
+func isCore15(d Display) bool {
+	major, minor := DisplayVersion(d)
+	return major > 1 || major == 1 && minor >= 5
+}
+
+// ClientExtensions returns the client extensions, which are available
+// before any display is initialized.
+func ClientExtensions() *ExtensionSet {
+	return DisplayExtensions(NO_DISPLAY)
+}
+
+// Extensions returns the extensions of the context's display.
+func (ctx *EGLContext) Extensions() *ExtensionSet {
+	return DisplayExtensions(ctx.display)
+}
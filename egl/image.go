@@ -0,0 +1,127 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import "errors"
+
+// EGL 1.5 / EGL_KHR_image_base and friends
+const (
+	IMAGE_PRESERVED_KHR  = 0x30D2
+	GL_TEXTURE_2D_KHR    = 0x30B1
+	GL_TEXTURE_LEVEL_KHR = 0x30BC
+
+	IMAGE_PRESERVED  = IMAGE_PRESERVED_KHR
+	GL_TEXTURE_2D    = GL_TEXTURE_2D_KHR
+	GL_TEXTURE_LEVEL = GL_TEXTURE_LEVEL_KHR
+)
+
+// EGL_EXT_image_dma_buf_import
+const (
+	LINUX_DMA_BUF_EXT         = 0x3270
+	LINUX_DRM_FOURCC_EXT      = 0x3271
+	DMA_BUF_PLANE0_FD_EXT     = 0x3272
+	DMA_BUF_PLANE0_OFFSET_EXT = 0x3273
+	DMA_BUF_PLANE0_PITCH_EXT  = 0x3274
+	DMA_BUF_PLANE1_FD_EXT     = 0x3275
+	DMA_BUF_PLANE1_OFFSET_EXT = 0x3276
+	DMA_BUF_PLANE1_PITCH_EXT  = 0x3277
+	DMA_BUF_PLANE2_FD_EXT     = 0x3278
+	DMA_BUF_PLANE2_OFFSET_EXT = 0x3279
+	DMA_BUF_PLANE2_PITCH_EXT  = 0x327A
+	DMA_BUF_PLANE3_FD_EXT     = 0x3440
+	DMA_BUF_PLANE3_OFFSET_EXT = 0x3441
+	DMA_BUF_PLANE3_PITCH_EXT  = 0x3442
+)
+
+// ErrNoImage is returned by CreateImage when the display supports neither
+// EGL 1.5 nor EGL_KHR_image_base.
+var ErrNoImage = errors.New("egl: EGLImage needs EGL 1.5 or EGL_KHR_image_base")
+
+// ErrNoDMABufImport is returned by CreateDMABufImage when the display
+// lacks EGL_EXT_image_dma_buf_import.
+var ErrNoDMABufImport = errors.New("egl: EGL_EXT_image_dma_buf_import is not supported")
+
+// ErrDMABufPlanes is returned by CreateDMABufImage for less than one or
+// more than four planes.
+var ErrDMABufPlanes = errors.New("egl: a dma-buf image needs 1 to 4 planes")
+
+// useCoreImage tells whether the EGL 1.5 image functions can be used on
+// display d.
+func useCoreImage(d Display) bool {
+	return isCore15(d) && Procs().CreateImage && Procs().DestroyImage
+}
+
+// CreateImage creates an EGLImage from buf, using eglCreateImage on EGL
+// 1.5 and eglCreateImageKHR otherwise. attribs must be terminated by NONE
+// or be nil.
+func CreateImage(d Display, c Context, target uint, buf ClientBuffer, attribs []EGLAttrib) (Image, error) {
+	return createImage(d, c, target, uintptr(buf), attribs)
+}
+
+func createImage(d Display, c Context, target uint, buf uintptr, attribs []EGLAttrib) (Image, error) {
+	if useCoreImage(d) {
+		return createImageCore(d, c, target, buf, attribs)
+	}
+	if !DisplayExtensions(d).Has(KHR_image_base) {
+		return NO_IMAGE, ErrNoImage
+	}
+	return createImageKHR(d, c, target, buf, intAttribs(attribs))
+}
+
+// DestroyImage destroys an image created by CreateImage.
+func DestroyImage(d Display, img Image) error {
+	if useCoreImage(d) {
+		return DestroyImageCore(d, img)
+	}
+	return DestroyImageKHR(d, img)
+}
+
+// CreateTextureImage creates an image sharing the storage of the given
+// mipmap level of a GL_TEXTURE_2D texture of context c.
+func CreateTextureImage(d Display, c Context, texture uint32, level int) (Image, error) {
+	return createImage(d, c, GL_TEXTURE_2D, uintptr(texture), []EGLAttrib{
+		GL_TEXTURE_LEVEL, EGLAttrib(level),
+		IMAGE_PRESERVED, TRUE,
+		NONE})
+}
+
+// DMABufPlane is one plane of a Linux dma-buf.
+type DMABufPlane struct {
+	FD     int
+	Offset int
+	Pitch  int
+}
+
+var dmaBufPlaneAttribs = [4][3]EGLAttrib{
+	{DMA_BUF_PLANE0_FD_EXT, DMA_BUF_PLANE0_OFFSET_EXT, DMA_BUF_PLANE0_PITCH_EXT},
+	{DMA_BUF_PLANE1_FD_EXT, DMA_BUF_PLANE1_OFFSET_EXT, DMA_BUF_PLANE1_PITCH_EXT},
+	{DMA_BUF_PLANE2_FD_EXT, DMA_BUF_PLANE2_OFFSET_EXT, DMA_BUF_PLANE2_PITCH_EXT},
+	{DMA_BUF_PLANE3_FD_EXT, DMA_BUF_PLANE3_OFFSET_EXT, DMA_BUF_PLANE3_PITCH_EXT},
+}
+
+// CreateDMABufImage imports a Linux dma-buf with the given DRM fourcc
+// format and up to four planes (EGL_EXT_image_dma_buf_import).
+func CreateDMABufImage(d Display, width, height int, fourcc uint32, planes []DMABufPlane) (Image, error) {
+	if !DisplayExtensions(d).Has(EXT_image_dma_buf_import) {
+		return NO_IMAGE, ErrNoDMABufImport
+	}
+	if len(planes) == 0 || len(planes) > len(dmaBufPlaneAttribs) {
+		return NO_IMAGE, ErrDMABufPlanes
+	}
+	attribs := []EGLAttrib{
+		WIDTH, EGLAttrib(width),
+		HEIGHT, EGLAttrib(height),
+		LINUX_DRM_FOURCC_EXT, EGLAttrib(fourcc),
+	}
+	for i, p := range planes {
+		names := dmaBufPlaneAttribs[i]
+		attribs = append(attribs,
+			names[0], EGLAttrib(p.FD),
+			names[1], EGLAttrib(p.Offset),
+			names[2], EGLAttrib(p.Pitch))
+	}
+	attribs = append(attribs, NONE)
+	return createImage(d, NO_CONTEXT, LINUX_DMA_BUF_EXT, 0, attribs)
+}
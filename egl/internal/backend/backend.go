@@ -0,0 +1,12 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package backend lets egltest replace the EGL function table of package
+// egl without making it part of egl's API.
+package backend
+
+// Set is installed by package egl. It makes b the function table of the
+// package and returns a function that restores the previous one. b must
+// implement every core EGL entry point egl calls; nil restores libEGL.
+var Set func(b interface{}) (restore func())
@@ -0,0 +1,70 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+// Loader uploads resources from a background OS thread, through a context
+// that shares objects with a primary context (see NewSharedContext).
+// Textures, buffers and other objects created by Upload can be used on
+// the primary context once the upload's fence is signaled.
+type Loader struct {
+	ctx    *EGLContext
+	thread *osThread
+}
+
+// NewLoader creates the shared context of primary on a new locked OS
+// thread.
+func NewLoader(primary *EGLContext) (*Loader, error) {
+	ctx := NewSharedContext(primary)
+	thread, err := startOSThread(func() error {
+		if !ctx.InitEGLSurface() || !ctx.InitEGLContext() {
+			err := ctx.Err()
+			ctx.Terminate()
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Loader{ctx: ctx, thread: thread}, nil
+}
+
+// Context returns the shared context of the loader. It is current on the
+// loader thread only.
+func (l *Loader) Context() *EGLContext {
+	return l.ctx
+}
+
+// Upload runs f on the loader thread and returns a fence that is signaled
+// once the GPU has executed the commands issued by f. Wait on it, or
+// ServerWait from the primary context, before using the uploaded objects.
+// If the display has no fence sync, Upload waits for the commands to
+// complete itself and returns a nil Sync.
+func (l *Loader) Upload(f func()) (*Sync, error) {
+	var sync *Sync
+	var err error
+	l.thread.run(func() {
+		f()
+		sync, err = NewFenceSync(l.ctx.display)
+		if err == ErrNoFenceSync {
+			sync, err = nil, WaitClientErr()
+			return
+		}
+		if err == nil {
+			// flush the loader context so the fence can signal
+			// while other threads wait for it
+			_, err = sync.Wait(0)
+		}
+	})
+	return sync, err
+}
+
+// Close destroys the shared context and ends the loader thread.
+func (l *Loader) Close() {
+	l.thread.stop(func() {
+		l.ctx.Terminate()
+		ReleaseThread()
+	})
+}
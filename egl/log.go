@@ -0,0 +1,51 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"unsafe"
+
+	"github.com/gooid/gl/internal/logging"
+)
+
+var logger logging.Hook
+
+// SetLogger sets the logger of the package. Records carry the fields
+// phase, display, config (the EGL_CONFIG_ID), error and code (the EGL
+// error code). A nil logger, the default, discards everything.
+func SetLogger(l *slog.Logger) {
+	logger.Set(l)
+}
+
+// Logger returns the logger set by SetLogger.
+func Logger() *slog.Logger {
+	return logger.Get()
+}
+
+// log writes a record about ctx to the package logger.
+func (ctx *EGLContext) log(level slog.Level, phase, msg string, err error) {
+	l, c := Logger(), context.Background()
+	if !l.Enabled(c, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("phase", phase),
+		slog.String("display", fmt.Sprintf("%p", unsafe.Pointer(ctx.display))),
+	}
+	// no EGL calls here: they would reset the error check() is about to read
+	if ctx.config != nil && ctx.info.ID != 0 {
+		attrs = append(attrs, slog.Int("config", ctx.info.ID))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		if code := ErrorCode(err); code != SUCCESS {
+			attrs = append(attrs, slog.String("code", fmt.Sprintf("0x%04x", int(code))))
+		}
+	}
+	l.LogAttrs(c, level, msg, attrs...)
+}
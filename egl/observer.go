@@ -0,0 +1,101 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import "log/slog"
+
+// Observer is told about the lifecycle of the surface and context of an
+// EGLContext. The methods are called on the thread that caused the event
+// (InitEGLSurface, Resume, Suspend, SwapBuffers, ...), and with the
+// context current unless noted otherwise, so GL resources can be
+// uploaded again and viewports set right away.
+type Observer interface {
+	// OnSurfaceCreated is called once a surface has been created, by
+	// InitEGLSurface, Resume or after a lost surface was recreated. The
+	// context may not exist or be current yet on the first call.
+	OnSurfaceCreated(ctx *EGLContext)
+	// OnSurfaceLost is called after the surface has been destroyed, by
+	// Suspend or because it went bad. GL must not draw until the next
+	// OnSurfaceCreated.
+	OnSurfaceLost(ctx *EGLContext)
+	// OnContextLost is called when the context has been lost. All GL
+	// objects of the context are gone.
+	OnContextLost(ctx *EGLContext)
+	// OnContextRecreated is called once a new context replaces a lost one
+	// and is current. GL objects must be created again.
+	OnContextRecreated(ctx *EGLContext)
+	// OnResize is called when the size of the surface changes, including
+	// once a new surface and the context are current together.
+	OnResize(ctx *EGLContext, width, height int)
+}
+
+// NopObserver implements Observer with empty methods, to be embedded by
+// observers interested in a few events only.
+type NopObserver struct{}
+
+func (NopObserver) OnSurfaceCreated(ctx *EGLContext)            {}
+func (NopObserver) OnSurfaceLost(ctx *EGLContext)               {}
+func (NopObserver) OnContextLost(ctx *EGLContext)               {}
+func (NopObserver) OnContextRecreated(ctx *EGLContext)          {}
+func (NopObserver) OnResize(ctx *EGLContext, width, height int) {}
+
+// SetObserver sets the observer of the context's lifecycle events, nil
+// to remove it. A new observer is told the size of the surface on the
+// next SwapBuffers.
+func (ctx *EGLContext) SetObserver(o Observer) {
+	ctx.observer = o
+	ctx.width, ctx.height = 0, 0
+}
+
+// surfaceCreated notifies the observer of a new surface and, if the
+// context is already current on it, of its size. InitEGLSurface runs
+// before the context exists; InitEGLContext reports the size then.
+func (ctx *EGLContext) surfaceCreated() {
+	if ctx.surface == NO_SURFACE {
+		return
+	}
+	if ctx.observer != nil {
+		ctx.observer.OnSurfaceCreated(ctx)
+	}
+	if ctx.context != NO_CONTEXT {
+		ctx.updateSize()
+	}
+}
+
+// updateSize records the size of the surface and notifies the observer
+// when it has changed. Without an observer there is nobody to tell, so
+// the surface is not queried on every frame.
+func (ctx *EGLContext) updateSize() {
+	if _, nop := ctx.observer.(NopObserver); ctx.observer == nil || nop {
+		return
+	}
+	w, h := ctx.Size()
+	if w == ctx.width && h == ctx.height {
+		return
+	}
+	ctx.width, ctx.height = w, h
+	ctx.log(slog.LevelDebug, "resize", "EGL surface resized", nil)
+	if ctx.observer != nil {
+		ctx.observer.OnResize(ctx, w, h)
+	}
+}
+
+func (ctx *EGLContext) surfaceLost() {
+	if ctx.observer != nil {
+		ctx.observer.OnSurfaceLost(ctx)
+	}
+}
+
+func (ctx *EGLContext) contextLost() {
+	if ctx.observer != nil {
+		ctx.observer.OnContextLost(ctx)
+	}
+}
+
+func (ctx *EGLContext) contextRecreated() {
+	if ctx.observer != nil {
+		ctx.observer.OnContextRecreated(ctx)
+	}
+}
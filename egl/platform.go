@@ -0,0 +1,128 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+/*
+#include <stdint.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// EGLAttrib is the pointer sized attribute type of EGL 1.5.
+type EGLAttrib int
+
+// Platform selects the native platform of GetPlatformDisplay.
+type Platform uint
+
+const (
+	PLATFORM_ANDROID_KHR      Platform = 0x3141
+	PLATFORM_DEVICE_EXT       Platform = 0x313F
+	PLATFORM_GBM_KHR          Platform = 0x31D7
+	PLATFORM_GBM_MESA         Platform = PLATFORM_GBM_KHR
+	PLATFORM_X11_KHR          Platform = 0x31D5
+	PLATFORM_X11_EXT          Platform = PLATFORM_X11_KHR
+	PLATFORM_WAYLAND_KHR      Platform = 0x31D8
+	PLATFORM_WAYLAND_EXT      Platform = PLATFORM_WAYLAND_KHR
+	PLATFORM_SURFACELESS_MESA Platform = 0x31DD
+)
+
+// GetPlatformDisplay attributes
+const (
+	PLATFORM_X11_SCREEN_KHR = 0x31D6
+	PLATFORM_X11_SCREEN_EXT = PLATFORM_X11_SCREEN_KHR
+)
+
+// ErrNoPlatformBase is returned when the client supports neither EGL 1.5
+// nor EGL_EXT_platform_base.
+var ErrNoPlatformBase = errors.New("egl: platform displays need EGL 1.5 or EGL_EXT_platform_base")
+
+type platformAPI int
+
+const (
+	platformNone platformAPI = iota
+	platformCore             // EGL 1.5
+	platformEXT              // EGL_EXT_platform_base
+)
+
+// clientPlatformAPI tells which flavour of the platform functions the
+// client library implements. Only EGL 1.5 clients answer a VERSION query
+// on NO_DISPLAY.
+func clientPlatformAPI() platformAPI {
+	if maj, min, ok := parseVersion(QueryString(NO_DISPLAY, VERSION)); ok && (maj > 1 || maj == 1 && min >= 5) {
+		return platformCore
+	}
+	if ClientExtensions().Has(EXT_platform_base) {
+		return platformEXT
+	}
+	return platformNone
+}
+
+// parseVersion parses the "<major>.<minor> <vendor info>" string returned
+// by a VERSION query.
+func parseVersion(s string) (major, minor int, ok bool) {
+	n, _ := fmt.Sscanf(s, "%d.%d", &major, &minor)
+	return major, minor, n == 2
+}
+
+// GetPlatformDisplay returns the display of native on the given platform,
+// using eglGetPlatformDisplay or eglGetPlatformDisplayEXT. attribs must be
+// terminated by NONE or be nil.
+func GetPlatformDisplay(platform Platform, native unsafe.Pointer, attribs []EGLAttrib) Display {
+	d, _ := GetPlatformDisplayErr(platform, native, attribs)
+	return d
+}
+
+func GetPlatformDisplayErr(platform Platform, native unsafe.Pointer, attribs []EGLAttrib) (Display, error) {
+	switch clientPlatformAPI() {
+	case platformCore:
+		return getPlatformDisplay(platform, native, attribs)
+	case platformEXT:
+		return getPlatformDisplayEXT(platform, native, intAttribs(attribs))
+	}
+	return NO_DISPLAY, ErrNoPlatformBase
+}
+
+// CreatePlatformWindowSurface creates a window surface on a display
+// obtained from GetPlatformDisplay. native points to the platform's
+// window object, e.g. a *Window XID for PLATFORM_X11_KHR.
+func CreatePlatformWindowSurface(d Display, conf Config, native unsafe.Pointer, attribs []EGLAttrib) Surface {
+	s, _ := CreatePlatformWindowSurfaceErr(d, conf, native, attribs)
+	return s
+}
+
+func CreatePlatformWindowSurfaceErr(d Display, conf Config, native unsafe.Pointer, attribs []EGLAttrib) (Surface, error) {
+	switch clientPlatformAPI() {
+	case platformCore:
+		return createPlatformWindowSurface(d, conf, native, attribs)
+	case platformEXT:
+		return createPlatformWindowSurfaceEXT(d, conf, native, intAttribs(attribs))
+	}
+	return NO_SURFACE, ErrNoPlatformBase
+}
+
+func attribPtrList(attribs []EGLAttrib) *C.intptr_t {
+	if len(attribs) == 0 {
+		return nil
+	}
+	return (*C.intptr_t)(unsafe.Pointer(&attribs[0]))
+}
+
+// intAttribs converts an EGL 1.5 attribute list for the EGLint based
+// extension entry points.
+func intAttribs(attribs []EGLAttrib) []EGLint {
+	if attribs == nil {
+		return nil
+	}
+	list := make([]EGLint, len(attribs))
+	for i, a := range attribs {
+		list[i] = EGLint(a)
+	}
+	return list
+}
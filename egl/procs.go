@@ -0,0 +1,554 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include <EGL/egl.h>
+
+typedef EGLDisplay (*GPGETPLATFORMDISPLAY)(EGLenum platform, void *native, const intptr_t *attribs);
+typedef EGLDisplay (*GPGETPLATFORMDISPLAYEXT)(EGLenum platform, void *native, const EGLint *attribs);
+typedef EGLSurface (*GPCREATEPLATFORMWINDOWSURFACE)(EGLDisplay dpy, EGLConfig config, void *native, const intptr_t *attribs);
+typedef EGLSurface (*GPCREATEPLATFORMWINDOWSURFACEEXT)(EGLDisplay dpy, EGLConfig config, void *native, const EGLint *attribs);
+typedef EGLBoolean (*GPQUERYDEVICESEXT)(EGLint max_devices, void **devices, EGLint *num_devices);
+typedef const char *(*GPQUERYDEVICESTRINGEXT)(void *device, EGLint name);
+typedef EGLBoolean (*GPQUERYDEVICEATTRIBEXT)(void *device, EGLint attribute, intptr_t *value);
+typedef EGLBoolean (*GPQUERYDISPLAYATTRIBEXT)(EGLDisplay dpy, EGLint attribute, intptr_t *value);
+typedef void *(*GPCREATEIMAGE)(EGLDisplay dpy, EGLContext ctx, EGLenum target, EGLClientBuffer buffer, const intptr_t *attribs);
+typedef void *(*GPCREATEIMAGEKHR)(EGLDisplay dpy, EGLContext ctx, EGLenum target, EGLClientBuffer buffer, const EGLint *attribs);
+typedef EGLBoolean (*GPDESTROYIMAGE)(EGLDisplay dpy, void *image);
+typedef void *(*GPCREATESYNC)(EGLDisplay dpy, EGLenum type, const intptr_t *attribs);
+typedef EGLint (*GPCLIENTWAITSYNC)(EGLDisplay dpy, void *sync, EGLint flags, uint64_t timeout);
+typedef EGLBoolean (*GPWAITSYNC)(EGLDisplay dpy, void *sync, EGLint flags);
+typedef EGLBoolean (*GPGETSYNCATTRIB)(EGLDisplay dpy, void *sync, EGLint attribute, intptr_t *value);
+typedef void *(*GPCREATESYNCKHR)(EGLDisplay dpy, EGLenum type, const EGLint *attribs);
+typedef EGLBoolean (*GPDESTROYSYNCKHR)(EGLDisplay dpy, void *sync);
+typedef EGLint (*GPCLIENTWAITSYNCKHR)(EGLDisplay dpy, void *sync, EGLint flags, uint64_t timeout);
+typedef EGLint (*GPWAITSYNCKHR)(EGLDisplay dpy, void *sync, EGLint flags);
+typedef EGLBoolean (*GPGETSYNCATTRIBKHR)(EGLDisplay dpy, void *sync, EGLint attribute, EGLint *value);
+typedef EGLBoolean (*GPSWAPBUFFERSWITHDAMAGE)(EGLDisplay dpy, EGLSurface surface, const EGLint *rects, EGLint n_rects);
+
+// EGLDisplay is a uintptr in Go; return it as a pointer so that it
+// converts to Display without going through uintptr.
+static void *glowGetPlatformDisplay(GPGETPLATFORMDISPLAY fnptr, EGLenum platform, void *native, const intptr_t *attribs) {
+	return (void *)(*fnptr)(platform, native, attribs);
+}
+static void *glowGetPlatformDisplayEXT(GPGETPLATFORMDISPLAYEXT fnptr, EGLenum platform, void *native, const EGLint *attribs) {
+	return (void *)(*fnptr)(platform, native, attribs);
+}
+static EGLSurface glowCreatePlatformWindowSurface(GPCREATEPLATFORMWINDOWSURFACE fnptr, EGLDisplay dpy, EGLConfig config, void *native, const intptr_t *attribs) {
+	return (*fnptr)(dpy, config, native, attribs);
+}
+static EGLSurface glowCreatePlatformWindowSurfaceEXT(GPCREATEPLATFORMWINDOWSURFACEEXT fnptr, EGLDisplay dpy, EGLConfig config, void *native, const EGLint *attribs) {
+	return (*fnptr)(dpy, config, native, attribs);
+}
+static EGLBoolean glowQueryDevicesEXT(GPQUERYDEVICESEXT fnptr, EGLint max_devices, void **devices, EGLint *num_devices) {
+	return (*fnptr)(max_devices, devices, num_devices);
+}
+static const char *glowQueryDeviceStringEXT(GPQUERYDEVICESTRINGEXT fnptr, void *device, EGLint name) {
+	return (*fnptr)(device, name);
+}
+static EGLBoolean glowQueryDeviceAttribEXT(GPQUERYDEVICEATTRIBEXT fnptr, void *device, EGLint attribute, intptr_t *value) {
+	return (*fnptr)(device, attribute, value);
+}
+static EGLBoolean glowQueryDisplayAttribEXT(GPQUERYDISPLAYATTRIBEXT fnptr, EGLDisplay dpy, EGLint attribute, intptr_t *value) {
+	return (*fnptr)(dpy, attribute, value);
+}
+// the attribute is a handle: convert it to a pointer on the C side
+static void *glowQueryDisplayPointerEXT(GPQUERYDISPLAYATTRIBEXT fnptr, EGLDisplay dpy, EGLint attribute, EGLBoolean *ok) {
+	intptr_t value = 0;
+	*ok = (*fnptr)(dpy, attribute, &value);
+	return (void *)value;
+}
+static void *glowCreateImage(GPCREATEIMAGE fnptr, EGLDisplay dpy, EGLContext ctx, EGLenum target, uintptr_t buffer, const intptr_t *attribs) {
+	return (*fnptr)(dpy, ctx, target, (EGLClientBuffer)buffer, attribs);
+}
+static void *glowCreateImageKHR(GPCREATEIMAGEKHR fnptr, EGLDisplay dpy, EGLContext ctx, EGLenum target, uintptr_t buffer, const EGLint *attribs) {
+	return (*fnptr)(dpy, ctx, target, (EGLClientBuffer)buffer, attribs);
+}
+static EGLBoolean glowDestroyImage(GPDESTROYIMAGE fnptr, EGLDisplay dpy, void *image) {
+	return (*fnptr)(dpy, image);
+}
+static void *glowCreateSync(GPCREATESYNC fnptr, EGLDisplay dpy, EGLenum type, const intptr_t *attribs) {
+	return (*fnptr)(dpy, type, attribs);
+}
+static EGLBoolean glowWaitSync(GPWAITSYNC fnptr, EGLDisplay dpy, void *sync, EGLint flags) {
+	return (*fnptr)(dpy, sync, flags);
+}
+static EGLBoolean glowGetSyncAttrib(GPGETSYNCATTRIB fnptr, EGLDisplay dpy, void *sync, EGLint attribute, intptr_t *value) {
+	return (*fnptr)(dpy, sync, attribute, value);
+}
+static void *glowCreateSyncKHR(GPCREATESYNCKHR fnptr, EGLDisplay dpy, EGLenum type, const EGLint *attribs) {
+	return (*fnptr)(dpy, type, attribs);
+}
+static EGLBoolean glowDestroySyncKHR(GPDESTROYSYNCKHR fnptr, EGLDisplay dpy, void *sync) {
+	return (*fnptr)(dpy, sync);
+}
+static EGLint glowClientWaitSyncKHR(GPCLIENTWAITSYNCKHR fnptr, EGLDisplay dpy, void *sync, EGLint flags, uint64_t timeout) {
+	return (*fnptr)(dpy, sync, flags, timeout);
+}
+static EGLint glowWaitSyncKHR(GPWAITSYNCKHR fnptr, EGLDisplay dpy, void *sync, EGLint flags) {
+	return (*fnptr)(dpy, sync, flags);
+}
+static EGLBoolean glowGetSyncAttribKHR(GPGETSYNCATTRIBKHR fnptr, EGLDisplay dpy, void *sync, EGLint attribute, EGLint *value) {
+	return (*fnptr)(dpy, sync, attribute, value);
+}
+static EGLBoolean glowSwapBuffersWithDamage(GPSWAPBUFFERSWITHDAMAGE fnptr, EGLDisplay dpy, EGLSurface surface, const EGLint *rects, EGLint n_rects) {
+	return (*fnptr)(dpy, surface, rects, n_rects);
+}
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+type (
+	Image   unsafe.Pointer
+	EGLSync unsafe.Pointer
+)
+
+var (
+	NO_IMAGE Image
+	NO_SYNC  EGLSync
+)
+
+// ProcError is returned by the wrapper of an extension entry point that
+// eglGetProcAddress could not resolve, or whose extension is not listed
+// by the display, or by the client for functions without a display.
+type ProcError struct {
+	Name string
+}
+
+func (e *ProcError) Error() string {
+	return "egl: " + e.Name + " is not available"
+}
+
+// hasExtension reports whether one of exts is listed by the display d,
+// or by the client if d is NO_DISPLAY. eglGetProcAddress may resolve the
+// functions of extensions a display does not support.
+func hasExtension(d Display, exts ...Extension) bool {
+	set := ClientExtensions()
+	if d != NO_DISPLAY {
+		set = DisplayExtensions(d)
+	}
+	for _, ext := range exts {
+		if set.Has(ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetProcAddress returns the address of an EGL or client API function,
+// or nil if it is unknown.
+func GetProcAddress(name string) unsafe.Pointer {
+	return be().GetProcAddress(name)
+}
+
+func (cBackend) GetProcAddress(name string) unsafe.Pointer {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return unsafe.Pointer(C.eglGetProcAddress(cname))
+}
+
+// ProcSet reports which extension entry points were resolved. A resolved
+// entry point may still be unusable on a display that does not list the
+// extension, check DisplayExtensions as well.
+type ProcSet struct {
+	GetPlatformDisplay             bool
+	GetPlatformDisplayEXT          bool
+	CreatePlatformWindowSurface    bool
+	CreatePlatformWindowSurfaceEXT bool
+	QueryDevicesEXT                bool
+	QueryDeviceStringEXT           bool
+	QueryDeviceAttribEXT           bool
+	QueryDisplayAttribEXT          bool
+	CreateImage                    bool
+	DestroyImage                   bool
+	CreateImageKHR                 bool
+	DestroyImageKHR                bool
+	CreateSync                     bool
+	DestroySync                    bool
+	ClientWaitSync                 bool
+	WaitSync                       bool
+	GetSyncAttrib                  bool
+	CreateSyncKHR                  bool
+	DestroySyncKHR                 bool
+	ClientWaitSyncKHR              bool
+	WaitSyncKHR                    bool
+	GetSyncAttribKHR               bool
+	SwapBuffersWithDamageKHR       bool
+	SwapBuffersWithDamageEXT       bool
+}
+
+var (
+	procsOnce sync.Once
+	procs     ProcSet
+
+	gpGetPlatformDisplay             C.GPGETPLATFORMDISPLAY
+	gpGetPlatformDisplayEXT          C.GPGETPLATFORMDISPLAYEXT
+	gpCreatePlatformWindowSurface    C.GPCREATEPLATFORMWINDOWSURFACE
+	gpCreatePlatformWindowSurfaceEXT C.GPCREATEPLATFORMWINDOWSURFACEEXT
+	gpQueryDevicesEXT                C.GPQUERYDEVICESEXT
+	gpQueryDeviceStringEXT           C.GPQUERYDEVICESTRINGEXT
+	gpQueryDeviceAttribEXT           C.GPQUERYDEVICEATTRIBEXT
+	gpQueryDisplayAttribEXT          C.GPQUERYDISPLAYATTRIBEXT
+	gpCreateImage                    C.GPCREATEIMAGE
+	gpDestroyImage                   C.GPDESTROYIMAGE
+	gpCreateImageKHR                 C.GPCREATEIMAGEKHR
+	gpDestroyImageKHR                C.GPDESTROYIMAGE
+	gpCreateSync                     C.GPCREATESYNC
+	gpDestroySync                    C.GPDESTROYSYNCKHR
+	gpClientWaitSync                 C.GPCLIENTWAITSYNCKHR
+	gpWaitSync                       C.GPWAITSYNC
+	gpGetSyncAttrib                  C.GPGETSYNCATTRIB
+	gpCreateSyncKHR                  C.GPCREATESYNCKHR
+	gpDestroySyncKHR                 C.GPDESTROYSYNCKHR
+	gpClientWaitSyncKHR              C.GPCLIENTWAITSYNCKHR
+	gpWaitSyncKHR                    C.GPWAITSYNCKHR
+	gpGetSyncAttribKHR               C.GPGETSYNCATTRIBKHR
+	gpSwapBuffersWithDamageKHR       C.GPSWAPBUFFERSWITHDAMAGE
+	gpSwapBuffersWithDamageEXT       C.GPSWAPBUFFERSWITHDAMAGE
+)
+
+// Procs resolves the extension entry points on first use and reports
+// which of them are available. The entry points come from libEGL, so none
+// is available while a fake backend (egltest) is installed.
+func Procs() ProcSet {
+	if !isLibEGL() {
+		return ProcSet{}
+	}
+	procsOnce.Do(loadProcs)
+	return procs
+}
+
+func loadProcs() {
+	gpGetPlatformDisplay = (C.GPGETPLATFORMDISPLAY)(GetProcAddress("eglGetPlatformDisplay"))
+	procs.GetPlatformDisplay = gpGetPlatformDisplay != nil
+	gpGetPlatformDisplayEXT = (C.GPGETPLATFORMDISPLAYEXT)(GetProcAddress("eglGetPlatformDisplayEXT"))
+	procs.GetPlatformDisplayEXT = gpGetPlatformDisplayEXT != nil
+	gpCreatePlatformWindowSurface = (C.GPCREATEPLATFORMWINDOWSURFACE)(GetProcAddress("eglCreatePlatformWindowSurface"))
+	procs.CreatePlatformWindowSurface = gpCreatePlatformWindowSurface != nil
+	gpCreatePlatformWindowSurfaceEXT = (C.GPCREATEPLATFORMWINDOWSURFACEEXT)(GetProcAddress("eglCreatePlatformWindowSurfaceEXT"))
+	procs.CreatePlatformWindowSurfaceEXT = gpCreatePlatformWindowSurfaceEXT != nil
+	gpQueryDevicesEXT = (C.GPQUERYDEVICESEXT)(GetProcAddress("eglQueryDevicesEXT"))
+	procs.QueryDevicesEXT = gpQueryDevicesEXT != nil
+	gpQueryDeviceStringEXT = (C.GPQUERYDEVICESTRINGEXT)(GetProcAddress("eglQueryDeviceStringEXT"))
+	procs.QueryDeviceStringEXT = gpQueryDeviceStringEXT != nil
+	gpQueryDeviceAttribEXT = (C.GPQUERYDEVICEATTRIBEXT)(GetProcAddress("eglQueryDeviceAttribEXT"))
+	procs.QueryDeviceAttribEXT = gpQueryDeviceAttribEXT != nil
+	gpQueryDisplayAttribEXT = (C.GPQUERYDISPLAYATTRIBEXT)(GetProcAddress("eglQueryDisplayAttribEXT"))
+	procs.QueryDisplayAttribEXT = gpQueryDisplayAttribEXT != nil
+	gpCreateImage = (C.GPCREATEIMAGE)(GetProcAddress("eglCreateImage"))
+	procs.CreateImage = gpCreateImage != nil
+	gpDestroyImage = (C.GPDESTROYIMAGE)(GetProcAddress("eglDestroyImage"))
+	procs.DestroyImage = gpDestroyImage != nil
+	gpCreateImageKHR = (C.GPCREATEIMAGEKHR)(GetProcAddress("eglCreateImageKHR"))
+	procs.CreateImageKHR = gpCreateImageKHR != nil
+	gpDestroyImageKHR = (C.GPDESTROYIMAGE)(GetProcAddress("eglDestroyImageKHR"))
+	procs.DestroyImageKHR = gpDestroyImageKHR != nil
+	gpCreateSync = (C.GPCREATESYNC)(GetProcAddress("eglCreateSync"))
+	procs.CreateSync = gpCreateSync != nil
+	gpDestroySync = (C.GPDESTROYSYNCKHR)(GetProcAddress("eglDestroySync"))
+	procs.DestroySync = gpDestroySync != nil
+	gpClientWaitSync = (C.GPCLIENTWAITSYNCKHR)(GetProcAddress("eglClientWaitSync"))
+	procs.ClientWaitSync = gpClientWaitSync != nil
+	gpWaitSync = (C.GPWAITSYNC)(GetProcAddress("eglWaitSync"))
+	procs.WaitSync = gpWaitSync != nil
+	gpGetSyncAttrib = (C.GPGETSYNCATTRIB)(GetProcAddress("eglGetSyncAttrib"))
+	procs.GetSyncAttrib = gpGetSyncAttrib != nil
+	gpCreateSyncKHR = (C.GPCREATESYNCKHR)(GetProcAddress("eglCreateSyncKHR"))
+	procs.CreateSyncKHR = gpCreateSyncKHR != nil
+	gpDestroySyncKHR = (C.GPDESTROYSYNCKHR)(GetProcAddress("eglDestroySyncKHR"))
+	procs.DestroySyncKHR = gpDestroySyncKHR != nil
+	gpClientWaitSyncKHR = (C.GPCLIENTWAITSYNCKHR)(GetProcAddress("eglClientWaitSyncKHR"))
+	procs.ClientWaitSyncKHR = gpClientWaitSyncKHR != nil
+	gpWaitSyncKHR = (C.GPWAITSYNCKHR)(GetProcAddress("eglWaitSyncKHR"))
+	procs.WaitSyncKHR = gpWaitSyncKHR != nil
+	gpGetSyncAttribKHR = (C.GPGETSYNCATTRIBKHR)(GetProcAddress("eglGetSyncAttribKHR"))
+	procs.GetSyncAttribKHR = gpGetSyncAttribKHR != nil
+	gpSwapBuffersWithDamageKHR = (C.GPSWAPBUFFERSWITHDAMAGE)(GetProcAddress("eglSwapBuffersWithDamageKHR"))
+	procs.SwapBuffersWithDamageKHR = gpSwapBuffersWithDamageKHR != nil
+	gpSwapBuffersWithDamageEXT = (C.GPSWAPBUFFERSWITHDAMAGE)(GetProcAddress("eglSwapBuffersWithDamageEXT"))
+	procs.SwapBuffersWithDamageEXT = gpSwapBuffersWithDamageEXT != nil
+}
+
+func getPlatformDisplay(platform Platform, native unsafe.Pointer, attribs []EGLAttrib) (Display, error) {
+	if !Procs().GetPlatformDisplay {
+		return NO_DISPLAY, &ProcError{"eglGetPlatformDisplay"}
+	}
+	defer lockThread()()
+	d := Display(C.glowGetPlatformDisplay(gpGetPlatformDisplay,
+		C.EGLenum(platform), native, attribPtrList(attribs)))
+	return d, check(d != NO_DISPLAY, "eglGetPlatformDisplay", platform, native, attribs)
+}
+
+func getPlatformDisplayEXT(platform Platform, native unsafe.Pointer, attribs []EGLint) (Display, error) {
+	if !Procs().GetPlatformDisplayEXT {
+		return NO_DISPLAY, &ProcError{"eglGetPlatformDisplayEXT"}
+	}
+	defer lockThread()()
+	d := Display(C.glowGetPlatformDisplayEXT(gpGetPlatformDisplayEXT,
+		C.EGLenum(platform), native, attribList(attribs)))
+	return d, check(d != NO_DISPLAY, "eglGetPlatformDisplayEXT", platform, native, attribs)
+}
+
+func createPlatformWindowSurface(d Display, conf Config, native unsafe.Pointer, attribs []EGLAttrib) (Surface, error) {
+	if !Procs().CreatePlatformWindowSurface {
+		return NO_SURFACE, &ProcError{"eglCreatePlatformWindowSurface"}
+	}
+	defer lockThread()()
+	s := Surface(C.glowCreatePlatformWindowSurface(gpCreatePlatformWindowSurface,
+		C.EGLDisplay(d), C.EGLConfig(conf), native, attribPtrList(attribs)))
+	return s, check(s != NO_SURFACE, "eglCreatePlatformWindowSurface", d, conf, native, attribs)
+}
+
+func createPlatformWindowSurfaceEXT(d Display, conf Config, native unsafe.Pointer, attribs []EGLint) (Surface, error) {
+	if !Procs().CreatePlatformWindowSurfaceEXT {
+		return NO_SURFACE, &ProcError{"eglCreatePlatformWindowSurfaceEXT"}
+	}
+	defer lockThread()()
+	s := Surface(C.glowCreatePlatformWindowSurfaceEXT(gpCreatePlatformWindowSurfaceEXT,
+		C.EGLDisplay(d), C.EGLConfig(conf), native, attribList(attribs)))
+	return s, check(s != NO_SURFACE, "eglCreatePlatformWindowSurfaceEXT", d, conf, native, attribs)
+}
+
+// QueryDevicesEXT fills devs and returns the number of devices written,
+// or the total number of devices if devs is empty.
+func QueryDevicesEXT(devs []Device) (int, error) {
+	if !Procs().QueryDevicesEXT || !hasExtension(NO_DISPLAY, EXT_device_enumeration, EXT_device_base) {
+		return 0, &ProcError{"eglQueryDevicesEXT"}
+	}
+	defer lockThread()()
+	var n C.EGLint
+	var p *unsafe.Pointer
+	if len(devs) > 0 {
+		p = (*unsafe.Pointer)(unsafe.Pointer(&devs[0]))
+	}
+	ok := goBool(C.glowQueryDevicesEXT(gpQueryDevicesEXT, C.EGLint(len(devs)), p, &n))
+	return int(n), check(ok, "eglQueryDevicesEXT", len(devs))
+}
+
+func QueryDeviceStringEXT(dev Device, name int) (string, error) {
+	if !Procs().QueryDeviceStringEXT || !hasExtension(NO_DISPLAY, EXT_device_query, EXT_device_base) {
+		return "", &ProcError{"eglQueryDeviceStringEXT"}
+	}
+	defer lockThread()()
+	s := C.glowQueryDeviceStringEXT(gpQueryDeviceStringEXT, unsafe.Pointer(dev), C.EGLint(name))
+	if s == nil {
+		return "", check(false, "eglQueryDeviceStringEXT", dev, name)
+	}
+	return C.GoString(s), nil
+}
+
+func QueryDeviceAttribEXT(dev Device, attr int) (EGLAttrib, error) {
+	if !Procs().QueryDeviceAttribEXT || !hasExtension(NO_DISPLAY, EXT_device_query, EXT_device_base) {
+		return 0, &ProcError{"eglQueryDeviceAttribEXT"}
+	}
+	defer lockThread()()
+	var val C.intptr_t
+	ok := goBool(C.glowQueryDeviceAttribEXT(gpQueryDeviceAttribEXT,
+		unsafe.Pointer(dev), C.EGLint(attr), &val))
+	return EGLAttrib(val), check(ok, "eglQueryDeviceAttribEXT", dev, attr)
+}
+
+func QueryDisplayAttribEXT(d Display, attr int) (EGLAttrib, error) {
+	if !Procs().QueryDisplayAttribEXT || !hasExtension(NO_DISPLAY, EXT_device_query, EXT_device_base) {
+		return 0, &ProcError{"eglQueryDisplayAttribEXT"}
+	}
+	defer lockThread()()
+	var val C.intptr_t
+	ok := goBool(C.glowQueryDisplayAttribEXT(gpQueryDisplayAttribEXT,
+		C.EGLDisplay(d), C.EGLint(attr), &val))
+	return EGLAttrib(val), check(ok, "eglQueryDisplayAttribEXT", d, attr)
+}
+
+// queryDisplayDeviceEXT is QueryDisplayAttribEXT(d, DEVICE_EXT) returning
+// the attribute as the Device handle it is.
+func queryDisplayDeviceEXT(d Display) (Device, error) {
+	if !Procs().QueryDisplayAttribEXT || !hasExtension(NO_DISPLAY, EXT_device_query, EXT_device_base) {
+		return NO_DEVICE, &ProcError{"eglQueryDisplayAttribEXT"}
+	}
+	defer lockThread()()
+	var ok C.EGLBoolean
+	dev := Device(C.glowQueryDisplayPointerEXT(gpQueryDisplayAttribEXT,
+		C.EGLDisplay(d), C.EGLint(DEVICE_EXT), &ok))
+	return dev, check(goBool(ok), "eglQueryDisplayAttribEXT", d, DEVICE_EXT)
+}
+
+// CreateImageCore creates an image with the EGL 1.5 eglCreateImage.
+func CreateImageCore(d Display, c Context, target uint, buf ClientBuffer, attribs []EGLAttrib) (Image, error) {
+	return createImageCore(d, c, target, uintptr(buf), attribs)
+}
+
+func createImageCore(d Display, c Context, target uint, buf uintptr, attribs []EGLAttrib) (Image, error) {
+	if !Procs().CreateImage || !isCore15(d) {
+		return NO_IMAGE, &ProcError{"eglCreateImage"}
+	}
+	defer lockThread()()
+	img := Image(C.glowCreateImage(gpCreateImage,
+		C.EGLDisplay(d), C.EGLContext(c), C.EGLenum(target), C.uintptr_t(buf),
+		attribPtrList(attribs)))
+	return img, check(img != NO_IMAGE, "eglCreateImage", d, c, target, buf, attribs)
+}
+
+func DestroyImageCore(d Display, img Image) error {
+	if !Procs().DestroyImage || !isCore15(d) {
+		return &ProcError{"eglDestroyImage"}
+	}
+	defer lockThread()()
+	ok := goBool(C.glowDestroyImage(gpDestroyImage, C.EGLDisplay(d), unsafe.Pointer(img)))
+	return check(ok, "eglDestroyImage", d, img)
+}
+
+func CreateImageKHR(d Display, c Context, target uint, buf ClientBuffer, attribs []EGLint) (Image, error) {
+	return createImageKHR(d, c, target, uintptr(buf), attribs)
+}
+
+func createImageKHR(d Display, c Context, target uint, buf uintptr, attribs []EGLint) (Image, error) {
+	if !Procs().CreateImageKHR || !hasExtension(d, KHR_image_base, KHR_image) {
+		return NO_IMAGE, &ProcError{"eglCreateImageKHR"}
+	}
+	defer lockThread()()
+	img := Image(C.glowCreateImageKHR(gpCreateImageKHR,
+		C.EGLDisplay(d), C.EGLContext(c), C.EGLenum(target), C.uintptr_t(buf),
+		attribList(attribs)))
+	return img, check(img != NO_IMAGE, "eglCreateImageKHR", d, c, target, buf, attribs)
+}
+
+func DestroyImageKHR(d Display, img Image) error {
+	if !Procs().DestroyImageKHR || !hasExtension(d, KHR_image_base, KHR_image) {
+		return &ProcError{"eglDestroyImageKHR"}
+	}
+	defer lockThread()()
+	ok := goBool(C.glowDestroyImage(gpDestroyImageKHR, C.EGLDisplay(d), unsafe.Pointer(img)))
+	return check(ok, "eglDestroyImageKHR", d, img)
+}
+
+// CreateSyncCore creates a sync object with the EGL 1.5 eglCreateSync.
+func CreateSyncCore(d Display, typ uint, attribs []EGLAttrib) (EGLSync, error) {
+	if !Procs().CreateSync || !isCore15(d) {
+		return NO_SYNC, &ProcError{"eglCreateSync"}
+	}
+	defer lockThread()()
+	s := EGLSync(C.glowCreateSync(gpCreateSync,
+		C.EGLDisplay(d), C.EGLenum(typ), attribPtrList(attribs)))
+	return s, check(s != NO_SYNC, "eglCreateSync", d, typ, attribs)
+}
+
+func DestroySyncCore(d Display, s EGLSync) error {
+	if !Procs().DestroySync || !isCore15(d) {
+		return &ProcError{"eglDestroySync"}
+	}
+	defer lockThread()()
+	ok := goBool(C.glowDestroySyncKHR(gpDestroySync, C.EGLDisplay(d), unsafe.Pointer(s)))
+	return check(ok, "eglDestroySync", d, s)
+}
+
+func ClientWaitSyncCore(d Display, s EGLSync, flags int, timeout uint64) (int, error) {
+	if !Procs().ClientWaitSync || !isCore15(d) {
+		return 0, &ProcError{"eglClientWaitSync"}
+	}
+	defer lockThread()()
+	ret := int(C.glowClientWaitSyncKHR(gpClientWaitSync,
+		C.EGLDisplay(d), unsafe.Pointer(s), C.EGLint(flags), C.uint64_t(timeout)))
+	return ret, check(ret != FALSE, "eglClientWaitSync", d, s, flags, timeout)
+}
+
+func WaitSyncCore(d Display, s EGLSync, flags int) error {
+	if !Procs().WaitSync || !isCore15(d) {
+		return &ProcError{"eglWaitSync"}
+	}
+	defer lockThread()()
+	ok := goBool(C.glowWaitSync(gpWaitSync, C.EGLDisplay(d), unsafe.Pointer(s), C.EGLint(flags)))
+	return check(ok, "eglWaitSync", d, s, flags)
+}
+
+func GetSyncAttribCore(d Display, s EGLSync, attr int) (EGLAttrib, error) {
+	if !Procs().GetSyncAttrib || !isCore15(d) {
+		return 0, &ProcError{"eglGetSyncAttrib"}
+	}
+	defer lockThread()()
+	var val C.intptr_t
+	ok := goBool(C.glowGetSyncAttrib(gpGetSyncAttrib,
+		C.EGLDisplay(d), unsafe.Pointer(s), C.EGLint(attr), &val))
+	return EGLAttrib(val), check(ok, "eglGetSyncAttrib", d, s, attr)
+}
+
+func CreateSyncKHR(d Display, typ uint, attribs []EGLint) (EGLSync, error) {
+	if !Procs().CreateSyncKHR || !hasExtension(d, KHR_fence_sync, KHR_reusable_sync) {
+		return NO_SYNC, &ProcError{"eglCreateSyncKHR"}
+	}
+	defer lockThread()()
+	s := EGLSync(C.glowCreateSyncKHR(gpCreateSyncKHR,
+		C.EGLDisplay(d), C.EGLenum(typ), attribList(attribs)))
+	return s, check(s != NO_SYNC, "eglCreateSyncKHR", d, typ, attribs)
+}
+
+func DestroySyncKHR(d Display, s EGLSync) error {
+	if !Procs().DestroySyncKHR || !hasExtension(d, KHR_fence_sync, KHR_reusable_sync) {
+		return &ProcError{"eglDestroySyncKHR"}
+	}
+	defer lockThread()()
+	ok := goBool(C.glowDestroySyncKHR(gpDestroySyncKHR, C.EGLDisplay(d), unsafe.Pointer(s)))
+	return check(ok, "eglDestroySyncKHR", d, s)
+}
+
+// ClientWaitSyncKHR blocks until s is signaled or timeout nanoseconds
+// have passed and returns the EGL status of the wait.
+func ClientWaitSyncKHR(d Display, s EGLSync, flags int, timeout uint64) (int, error) {
+	if !Procs().ClientWaitSyncKHR || !hasExtension(d, KHR_fence_sync, KHR_reusable_sync) {
+		return 0, &ProcError{"eglClientWaitSyncKHR"}
+	}
+	defer lockThread()()
+	ret := int(C.glowClientWaitSyncKHR(gpClientWaitSyncKHR,
+		C.EGLDisplay(d), unsafe.Pointer(s), C.EGLint(flags), C.uint64_t(timeout)))
+	return ret, check(ret != FALSE, "eglClientWaitSyncKHR", d, s, flags, timeout)
+}
+
+func WaitSyncKHR(d Display, s EGLSync, flags int) error {
+	if !Procs().WaitSyncKHR || !hasExtension(d, KHR_wait_sync) {
+		return &ProcError{"eglWaitSyncKHR"}
+	}
+	defer lockThread()()
+	ret := C.glowWaitSyncKHR(gpWaitSyncKHR, C.EGLDisplay(d), unsafe.Pointer(s), C.EGLint(flags))
+	return check(ret != FALSE, "eglWaitSyncKHR", d, s, flags)
+}
+
+func GetSyncAttribKHR(d Display, s EGLSync, attr int) (int, error) {
+	if !Procs().GetSyncAttribKHR || !hasExtension(d, KHR_fence_sync, KHR_reusable_sync) {
+		return 0, &ProcError{"eglGetSyncAttribKHR"}
+	}
+	defer lockThread()()
+	var val C.EGLint
+	ok := goBool(C.glowGetSyncAttribKHR(gpGetSyncAttribKHR,
+		C.EGLDisplay(d), unsafe.Pointer(s), C.EGLint(attr), &val))
+	return int(val), check(ok, "eglGetSyncAttribKHR", d, s, attr)
+}
+
+// SwapBuffersWithDamageKHR presents only the given rectangles of the
+// surface. rects holds x, y, width, height quadruples.
+func SwapBuffersWithDamageKHR(d Display, s Surface, rects []EGLint) error {
+	if !Procs().SwapBuffersWithDamageKHR || !hasExtension(d, KHR_swap_buffers_with_damage) {
+		return &ProcError{"eglSwapBuffersWithDamageKHR"}
+	}
+	defer lockThread()()
+	ok := goBool(C.glowSwapBuffersWithDamage(gpSwapBuffersWithDamageKHR,
+		C.EGLDisplay(d), C.EGLSurface(s), attribList(rects), C.EGLint(len(rects)/4)))
+	return check(ok, "eglSwapBuffersWithDamageKHR", d, s, rects)
+}
+
+func SwapBuffersWithDamageEXT(d Display, s Surface, rects []EGLint) error {
+	if !Procs().SwapBuffersWithDamageEXT || !hasExtension(d, EXT_swap_buffers_with_damage) {
+		return &ProcError{"eglSwapBuffersWithDamageEXT"}
+	}
+	defer lockThread()()
+	ok := goBool(C.glowSwapBuffersWithDamage(gpSwapBuffersWithDamageEXT,
+		C.EGLDisplay(d), C.EGLSurface(s), attribList(rects), C.EGLint(len(rects)/4)))
+	return check(ok, "eglSwapBuffersWithDamageEXT", d, s, rects)
+}
@@ -0,0 +1,200 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gooid/gl/egl"
+	"github.com/gooid/gl/egl/egltest"
+)
+
+// recorder is an Observer that records the events it gets.
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) OnSurfaceCreated(ctx *egl.EGLContext)   { r.add("surface created") }
+func (r *recorder) OnSurfaceLost(ctx *egl.EGLContext)      { r.add("surface lost") }
+func (r *recorder) OnContextLost(ctx *egl.EGLContext)      { r.add("context lost") }
+func (r *recorder) OnContextRecreated(ctx *egl.EGLContext) { r.add("context recreated") }
+func (r *recorder) OnResize(ctx *egl.EGLContext, width, height int) {
+	r.add("resize")
+}
+
+func (r *recorder) add(event string) {
+	r.events = append(r.events, event)
+}
+
+// take returns the events recorded since the last call.
+func (r *recorder) take() []string {
+	events := r.events
+	r.events = nil
+	return events
+}
+
+// newContext installs b and returns an initialized window context.
+func newContext(t *testing.T, b *egltest.Backend) (*egl.EGLContext, *recorder) {
+	t.Helper()
+	t.Cleanup(b.Install())
+	ctx := egl.NewContext(egltest.Window(1), nil)
+	rec := &recorder{}
+	ctx.SetObserver(rec)
+	if !ctx.InitEGLSurface() || !ctx.InitEGLContext() {
+		t.Fatalf("init: %v", ctx.Err())
+	}
+	t.Cleanup(ctx.Terminate)
+	rec.take()
+	return ctx, rec
+}
+
+func checkEvents(t *testing.T, rec *recorder, want ...string) {
+	t.Helper()
+	if got := rec.take(); !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+}
+
+func TestSwapBuffersContextLost(t *testing.T) {
+	b := egltest.New()
+	ctx, rec := newContext(t, b)
+	old := ctx.Context()
+
+	b.LoseContexts()
+	if ctx.SwapBuffers() {
+		t.Error("SwapBuffers of a lost context succeeded")
+	}
+	checkEvents(t, rec, "context lost", "surface created", "resize", "context recreated")
+	if ctx.Context() == old || ctx.Context() == egl.NO_CONTEXT {
+		t.Errorf("context = %p after recovery, was %p", ctx.Context(), old)
+	}
+	if b.Count("eglTerminate") != 1 || b.Count("eglInitialize") != 2 {
+		t.Errorf("display not rebuilt: %d terminates, %d initializes",
+			b.Count("eglTerminate"), b.Count("eglInitialize"))
+	}
+
+	if !ctx.SwapBuffers() {
+		t.Fatalf("SwapBuffers after recovery failed: %v", egl.GetError())
+	}
+	if n := b.Swaps(egl.GetCurrentSurface(egl.DRAW)); n != 1 {
+		t.Errorf("swaps of the new surface = %d, want 1", n)
+	}
+	if s, c := b.Live(); s != 1 || c != 1 {
+		t.Errorf("live surfaces, contexts = %d, %d, want 1, 1", s, c)
+	}
+}
+
+func TestSwapBuffersBadSurface(t *testing.T) {
+	b := egltest.New()
+	ctx, rec := newContext(t, b)
+	context := ctx.Context()
+	surface := egl.GetCurrentSurface(egl.DRAW)
+
+	b.Fail("eglSwapBuffers", egl.BAD_SURFACE, 1)
+	if !ctx.SwapBuffers() {
+		t.Fatal("SwapBuffers with a bad surface lost the context")
+	}
+	checkEvents(t, rec, "surface lost", "surface created")
+	if ctx.Context() != context {
+		t.Error("context was recreated for a bad surface")
+	}
+	if b.Count("eglCreateContext") != 1 || b.Count("eglTerminate") != 0 {
+		t.Errorf("%d context creations, %d terminates, want 1, 0",
+			b.Count("eglCreateContext"), b.Count("eglTerminate"))
+	}
+	if egl.GetCurrentSurface(egl.DRAW) == surface {
+		t.Error("surface was not recreated")
+	}
+
+	if !ctx.SwapBuffers() {
+		t.Fatalf("SwapBuffers after recreating the surface failed: %v", egl.GetError())
+	}
+	if s, c := b.Live(); s != 1 || c != 1 {
+		t.Errorf("live surfaces, contexts = %d, %d, want 1, 1", s, c)
+	}
+}
+
+func TestResumeContextLost(t *testing.T) {
+	b := egltest.New()
+	ctx, rec := newContext(t, b)
+
+	ctx.Suspend()
+	checkEvents(t, rec, "surface lost")
+	b.LoseContexts()
+	if !ctx.Resume() {
+		t.Fatalf("Resume: %v", ctx.Err())
+	}
+	checkEvents(t, rec, "context lost", "surface created", "resize", "context recreated")
+	if !ctx.SwapBuffers() {
+		t.Fatalf("SwapBuffers after Resume failed: %v", egl.GetError())
+	}
+	if s, c := b.Live(); s != 1 || c != 1 {
+		t.Errorf("live surfaces, contexts = %d, %d, want 1, 1", s, c)
+	}
+}
+
+func TestResumeSurfaceError(t *testing.T) {
+	b := egltest.New()
+	ctx, rec := newContext(t, b)
+
+	ctx.Suspend()
+	rec.take()
+	b.Fail("eglCreateWindowSurface", egl.BAD_NATIVE_WINDOW, 1)
+	if ctx.Resume() {
+		t.Fatal("Resume succeeded without a surface")
+	}
+	if code := egl.ErrorCode(ctx.Err()); code != egl.BAD_NATIVE_WINDOW {
+		t.Errorf("Err() = %v, want BAD_NATIVE_WINDOW", ctx.Err())
+	}
+	if b.Count("eglTerminate") != 0 {
+		t.Error("a surface error tore down the display")
+	}
+	checkEvents(t, rec)
+
+	if !ctx.Resume() {
+		t.Fatalf("second Resume: %v", ctx.Err())
+	}
+	checkEvents(t, rec, "surface created")
+}
+
+func TestSwapBuffersBadSurfaceRecreateFails(t *testing.T) {
+	b := egltest.New()
+	ctx, rec := newContext(t, b)
+
+	b.Fail("eglSwapBuffers", egl.BAD_SURFACE, 1)
+	b.Fail("eglCreateWindowSurface", egl.BAD_NATIVE_WINDOW, 1)
+	if ctx.SwapBuffers() {
+		t.Fatal("SwapBuffers succeeded without a surface")
+	}
+	checkEvents(t, rec, "surface lost")
+	if ctx.IsReady() {
+		t.Error("context without a surface is ready")
+	}
+}
+
+func TestResumeForgetsCurrentDrawable(t *testing.T) {
+	b := egltest.New()
+	ctx, _ := newContext(t, b)
+
+	d, err := ctx.AttachPbuffer(8, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.MakeCurrent(d, d); err != nil {
+		t.Fatal(err)
+	}
+	ctx.Suspend()
+	if !ctx.Resume() {
+		t.Fatalf("Resume: %v", ctx.Err())
+	}
+	n := b.Count("eglMakeCurrent")
+	if err := d.Detach(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Count("eglMakeCurrent") != n {
+		t.Error("Detach made the context current again for a drawable that was no longer current")
+	}
+}
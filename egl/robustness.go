@@ -0,0 +1,70 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import (
+	"errors"
+	"log/slog"
+)
+
+// EGL_EXT_create_context_robustness
+const (
+	CONTEXT_OPENGL_ROBUST_ACCESS_EXT               = 0x30BF
+	CONTEXT_OPENGL_RESET_NOTIFICATION_STRATEGY_EXT = 0x3138
+	NO_RESET_NOTIFICATION_EXT                      = 0x31BE
+	LOSE_CONTEXT_ON_RESET_EXT                      = 0x31BF
+)
+
+// robustAttribs ask for a context that reports GPU resets through
+// glGetGraphicsResetStatusEXT and is lost after one.
+var robustAttribs = []EGLint{
+	CONTEXT_OPENGL_ROBUST_ACCESS_EXT, TRUE,
+	CONTEXT_OPENGL_RESET_NOTIFICATION_STRATEGY_EXT, LOSE_CONTEXT_ON_RESET_EXT,
+}
+
+var errNoOpenDisplay = errors.New("egl: the display of the context cannot be opened again")
+
+// SetRobust asks InitEGLContext for a robust context with the
+// LOSE_CONTEXT_ON_RESET notification strategy (EGL_EXT_create_context_robustness).
+// A robust context is lost after a GPU reset instead of hanging or
+// crashing the process; glGetGraphicsResetStatusEXT then returns
+// non-zero, and Recover builds a new context. Without the extension a
+// regular context is created, see IsRobust.
+func (ctx *EGLContext) SetRobust(robust bool) {
+	ctx.opts.Robust = robust
+}
+
+// IsRobust reports whether the context was created robust.
+func (ctx *EGLContext) IsRobust() bool {
+	return ctx.obtained.Robust
+}
+
+// Recover rebuilds the display connection, the surface and the context
+// after the context has been lost, e.g. when a robust context saw a GPU
+// reset. The observer gets OnContextLost before and, on success,
+// OnSurfaceCreated and OnContextRecreated after; all GL objects must be
+// created again. SwapBuffers and Resume call Recover themselves when EGL
+// reports CONTEXT_LOST.
+func (ctx *EGLContext) Recover() bool {
+	ctx.log(slog.LevelWarn, "recover", "EGL context lost, recovering", nil)
+	ctx.contextLost()
+	ctx.Terminate()
+	if ctx.openDisplay == nil {
+		ctx.err = errNoOpenDisplay
+		return false
+	}
+	display, err := ctx.openDisplay()
+	if err != nil {
+		ctx.log(slog.LevelError, "recover", "EGL reopen display failed", err)
+		ctx.err = err
+		return false
+	}
+	ctx.display = display
+	if !ctx.InitEGLSurface() || !ctx.InitEGLContext() {
+		return false
+	}
+	ctx.contextRecreated()
+	return true
+}
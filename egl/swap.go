@@ -0,0 +1,100 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+// EGL_EXT_buffer_age
+const (
+	BUFFER_AGE_EXT = 0x313D
+)
+
+// Rect is a damaged region of a surface, in pixels with the origin at the
+// bottom left corner as in GL.
+type Rect struct {
+	X, Y, Width, Height int
+}
+
+func rectList(rects []Rect) []EGLint {
+	list := make([]EGLint, 0, 4*len(rects))
+	for _, r := range rects {
+		list = append(list, EGLint(r.X), EGLint(r.Y), EGLint(r.Width), EGLint(r.Height))
+	}
+	return list
+}
+
+// SwapBuffersWithDamage posts the back buffer of s telling the compositor
+// that only rects have changed, using EGL_KHR_swap_buffers_with_damage or
+// EGL_EXT_swap_buffers_with_damage. Without either extension, or with no
+// rects, the whole surface is swapped.
+func SwapBuffersWithDamage(d Display, s Surface, rects []Rect) error {
+	if len(rects) > 0 {
+		ext := DisplayExtensions(d)
+		switch {
+		case ext.Has(KHR_swap_buffers_with_damage) && Procs().SwapBuffersWithDamageKHR:
+			return SwapBuffersWithDamageKHR(d, s, rectList(rects))
+		case ext.Has(EXT_swap_buffers_with_damage) && Procs().SwapBuffersWithDamageEXT:
+			return SwapBuffersWithDamageEXT(d, s, rectList(rects))
+		}
+	}
+	return SwapBuffersErr(d, s)
+}
+
+// BufferAge returns the age of the back buffer of s: the number of swaps
+// since its content was current, 1 for the previous frame. 0 means the
+// content is undefined and everything must be repainted, which is also
+// the answer without EGL_EXT_buffer_age.
+func BufferAge(d Display, s Surface) (int, error) {
+	if !DisplayExtensions(d).Has(EXT_buffer_age) {
+		return 0, nil
+	}
+	age, err := QuerySurfaceErr(d, s, BUFFER_AGE_EXT)
+	return int(age), err
+}
+
+// SetSwapInterval sets the minimum number of video frames between buffer
+// swaps of the current surface: 0 disables vsync, 1 syncs every frame.
+// The context must be current on the calling thread.
+func (ctx *EGLContext) SetSwapInterval(interval int) error {
+	return SwapIntervalErr(ctx.display, interval)
+}
+
+// SwapBuffersWithDamage is SwapBuffers with a list of damaged regions,
+// see the package function SwapBuffersWithDamage.
+func (ctx *EGLContext) SwapBuffersWithDamage(rects []Rect) bool {
+	if ctx.surfaceless {
+		return true
+	}
+	err := SwapBuffersWithDamage(ctx.display, ctx.surface, rects)
+	if err == nil {
+		ctx.updateSize()
+		return true
+	}
+	return ctx.swapFailed(ErrorCode(err))
+}
+
+// BufferAge returns the age of the back buffer of the surface, see the
+// package function BufferAge.
+func (ctx *EGLContext) BufferAge() int {
+	if ctx.surfaceless {
+		return 0
+	}
+	age, _ := BufferAge(ctx.display, ctx.surface)
+	return age
+}
+
+// SwapWithDamage is Swap with a list of damaged regions.
+func (d *Drawable) SwapWithDamage(rects []Rect) error {
+	if d.surface == NO_SURFACE {
+		return errDetached
+	}
+	return SwapBuffersWithDamage(d.ctx.display, d.surface, rects)
+}
+
+// BufferAge returns the age of the back buffer of the drawable.
+func (d *Drawable) BufferAge() (int, error) {
+	if d.surface == NO_SURFACE {
+		return 0, errDetached
+	}
+	return BufferAge(d.ctx.display, d.surface)
+}
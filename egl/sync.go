@@ -0,0 +1,162 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// EGL 1.5 / EGL_KHR_fence_sync / EGL_KHR_wait_sync
+const (
+	SYNC_PRIOR_COMMANDS_COMPLETE = 0x30F0
+	SYNC_STATUS                  = 0x30F1
+	SIGNALED                     = 0x30F2
+	UNSIGNALED                   = 0x30F3
+	TIMEOUT_EXPIRED              = 0x30F5
+	CONDITION_SATISFIED          = 0x30F6
+	SYNC_TYPE                    = 0x30F7
+	SYNC_CONDITION               = 0x30F8
+	SYNC_FENCE                   = 0x30F9
+	SYNC_FLUSH_COMMANDS_BIT      = 0x0001
+	FOREVER                      = 0xFFFFFFFFFFFFFFFF
+)
+
+// ErrNoFenceSync is returned by NewFenceSync when the display supports
+// neither EGL 1.5 nor EGL_KHR_fence_sync.
+var ErrNoFenceSync = errors.New("egl: fence sync needs EGL 1.5 or EGL_KHR_fence_sync")
+
+// ErrNoWaitSync is returned by Sync.ServerWait when the display supports
+// neither EGL 1.5 nor EGL_KHR_wait_sync.
+var ErrNoWaitSync = errors.New("egl: server waits need EGL 1.5 or EGL_KHR_wait_sync")
+
+// syncPollInterval bounds each wait of WaitContext, so that cancellation
+// is noticed.
+const syncPollInterval = 2 * time.Millisecond
+
+// Sync is a fence inserted into the command stream of the current
+// context. It is signaled once the GPU has executed every command issued
+// before it, which lets another context or thread consume the result
+// without a glFinish.
+type Sync struct {
+	display Display
+	handle  EGLSync
+	core    bool
+}
+
+// useCoreSync tells whether the EGL 1.5 sync functions can be used on
+// display d.
+func useCoreSync(d Display) bool {
+	p := Procs()
+	return isCore15(d) &&
+		p.CreateSync && p.DestroySync && p.ClientWaitSync && p.GetSyncAttrib
+}
+
+// NewFenceSync creates a fence in the context current on the calling
+// thread. When the fence is waited on from another thread, the producer
+// must glFlush after creating it or the fence may never signal.
+func NewFenceSync(d Display) (*Sync, error) {
+	if useCoreSync(d) {
+		h, err := CreateSyncCore(d, SYNC_FENCE, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &Sync{display: d, handle: h, core: true}, nil
+	}
+	if !DisplayExtensions(d).Has(KHR_fence_sync) {
+		return nil, ErrNoFenceSync
+	}
+	h, err := CreateSyncKHR(d, SYNC_FENCE, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Sync{display: d, handle: h}, nil
+}
+
+func (s *Sync) clientWait(flags int, timeout uint64) (int, error) {
+	if s.core {
+		return ClientWaitSyncCore(s.display, s.handle, flags, timeout)
+	}
+	return ClientWaitSyncKHR(s.display, s.handle, flags, timeout)
+}
+
+// Wait blocks until the fence is signaled or timeout has passed, and
+// reports whether it was signaled. A negative timeout waits forever,
+// zero only polls. Commands of the calling thread's context are flushed
+// first.
+func (s *Sync) Wait(timeout time.Duration) (bool, error) {
+	t := uint64(FOREVER)
+	if timeout >= 0 {
+		t = uint64(timeout)
+	}
+	ret, err := s.clientWait(SYNC_FLUSH_COMMANDS_BIT, t)
+	if err != nil {
+		return false, err
+	}
+	return ret == CONDITION_SATISFIED, nil
+}
+
+// WaitContext blocks until the fence is signaled or ctx is done.
+func (s *Sync) WaitContext(ctx context.Context) error {
+	flags := SYNC_FLUSH_COMMANDS_BIT
+	for {
+		ret, err := s.clientWait(flags, uint64(syncPollInterval))
+		if err != nil {
+			return err
+		}
+		if ret == CONDITION_SATISFIED {
+			return nil
+		}
+		flags = 0
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+}
+
+// ServerWait makes the GPU of the calling thread's context wait for the
+// fence before executing further commands, without blocking the caller.
+// It needs EGL 1.5 or EGL_KHR_wait_sync.
+func (s *Sync) ServerWait() error {
+	if s.core {
+		return WaitSyncCore(s.display, s.handle, 0)
+	}
+	if !DisplayExtensions(s.display).Has(KHR_wait_sync) {
+		return ErrNoWaitSync
+	}
+	return WaitSyncKHR(s.display, s.handle, 0)
+}
+
+// Signaled reports whether the fence has been signaled, without waiting.
+func (s *Sync) Signaled() (bool, error) {
+	var status int
+	var err error
+	if s.core {
+		var v EGLAttrib
+		v, err = GetSyncAttribCore(s.display, s.handle, SYNC_STATUS)
+		status = int(v)
+	} else {
+		status, err = GetSyncAttribKHR(s.display, s.handle, SYNC_STATUS)
+	}
+	return status == SIGNALED, err
+}
+
+// Destroy releases the fence. Pending waits return once it is signaled.
+func (s *Sync) Destroy() error {
+	if s.handle == NO_SYNC {
+		return nil
+	}
+	var err error
+	if s.core {
+		err = DestroySyncCore(s.display, s.handle)
+	} else {
+		err = DestroySyncKHR(s.display, s.handle)
+	}
+	s.handle = NO_SYNC
+	return err
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import "runtime"
+
+// osThread runs functions on a single locked OS thread, which is what EGL
+// and GL need: a context is current on a thread, not on a goroutine.
+type osThread struct {
+	work chan func()
+	done chan struct{}
+}
+
+// startOSThread starts the thread and runs init on it. The thread is not
+// started if init fails.
+func startOSThread(init func() error) (*osThread, error) {
+	t := &osThread{
+		work: make(chan func(), 16),
+		done: make(chan struct{}),
+	}
+	errc := make(chan error, 1)
+	go func() {
+		// never unlocked: the OS thread, and any EGL state left on
+		// it, goes away together with the goroutine
+		runtime.LockOSThread()
+		defer close(t.done)
+		if err := init(); err != nil {
+			errc <- err
+			return
+		}
+		errc <- nil
+		for f := range t.work {
+			f()
+		}
+	}()
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// run runs f on the thread and waits for it to return.
+func (t *osThread) run(f func()) {
+	done := make(chan struct{})
+	t.work <- func() {
+		defer close(done)
+		f()
+	}
+	<-done
+}
+
+// runAsync queues f on the thread.
+func (t *osThread) runAsync(f func()) {
+	t.work <- f
+}
+
+// stop runs fini on the thread after all queued work and ends the thread.
+func (t *osThread) stop(fini func()) {
+	t.work <- fini
+	close(t.work)
+	<-t.done
+}
+
+// RenderThread owns an EGLContext and keeps it current on a dedicated,
+// locked OS thread. Any goroutine can hand GL work to it with Do or
+// DoAsync.
+type RenderThread struct {
+	ctx    *EGLContext
+	thread *osThread
+}
+
+// NewRenderThread starts a render thread for ctx. A ctx that has not been
+// initialized yet is initialized on the new thread; otherwise it is made
+// current there, which fails if it is still current on another thread.
+// If the initialization fails, ctx is terminated.
+func NewRenderThread(ctx *EGLContext) (*RenderThread, error) {
+	thread, err := startOSThread(func() error {
+		if ctx.context == NO_CONTEXT {
+			if !ctx.InitEGLSurface() || !ctx.InitEGLContext() {
+				err := ctx.Err()
+				ctx.Terminate()
+				return err
+			}
+			return nil
+		}
+		if err := MakeCurrentErr(ctx.display, ctx.surface, ctx.surface, ctx.context); err != nil {
+			return err
+		}
+		ctx.draw, ctx.read = nil, nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RenderThread{ctx: ctx, thread: thread}, nil
+}
+
+// Context returns the context owned by the render thread. Its methods
+// must only be called from functions run by Do or DoAsync.
+func (r *RenderThread) Context() *EGLContext {
+	return r.ctx
+}
+
+// Do runs f on the render thread, with the context current, and waits for
+// it to return. Calling Do from f deadlocks.
+func (r *RenderThread) Do(f func()) {
+	r.thread.run(f)
+}
+
+// DoAsync queues f on the render thread and returns immediately. Queued
+// functions run in order.
+func (r *RenderThread) DoAsync(f func()) {
+	r.thread.runAsync(f)
+}
+
+// Stop runs the queued functions, terminates the context and ends the
+// render thread. The RenderThread must not be used afterwards.
+func (r *RenderThread) Stop() {
+	r.thread.stop(func() {
+		r.ctx.Terminate()
+		ReleaseThread()
+	})
+}
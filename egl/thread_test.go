@@ -0,0 +1,34 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl_test
+
+import (
+	"testing"
+
+	"github.com/gooid/gl/egl"
+	"github.com/gooid/gl/egl/egltest"
+)
+
+func TestNewRenderThreadInitError(t *testing.T) {
+	b := egltest.New()
+	defer b.Install()()
+
+	ctx := egl.NewContext(egltest.Window(1), nil)
+	b.Fail("eglCreateContext", egl.BAD_ALLOC, -1)
+	r, err := egl.NewRenderThread(ctx)
+	if err == nil {
+		r.Stop()
+		t.Fatal("NewRenderThread succeeded without a context")
+	}
+	if code := egl.ErrorCode(err); code != egl.BAD_ALLOC {
+		t.Errorf("err = %v, want BAD_ALLOC", err)
+	}
+	if s, c := b.Live(); s != 0 || c != 0 {
+		t.Errorf("live surfaces, contexts = %d, %d after the failure", s, c)
+	}
+	if n := b.Count("eglTerminate"); n != 1 {
+		t.Errorf("%d eglTerminate, want the display terminated", n)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl
+
+import "runtime"
+
+// SetTranslucent asks for a surface that can be composited over other
+// content: a config with at least 8 bits of alpha, preferably with a
+// TRANSPARENT_RGB transparent type, and premultiplied alpha
+// (VG_ALPHA_FORMAT_PRE) where the config supports it. It overrides the
+// ConfigSpec only while enabled, leaving the spec itself unchanged, and
+// takes effect on the next InitEGLSurface. IsTranslucent tells what was
+// obtained.
+func (ctx *EGLContext) SetTranslucent(translucent bool) {
+	ctx.translucent = translucent
+}
+
+// chooseSpec returns the spec InitEGLSurface chooses the config with: the
+// ConfigSpec with the alpha asked for by SetTranslucent.
+func (ctx *EGLContext) chooseSpec() ConfigSpec {
+	spec := ctx.spec
+	if ctx.translucent {
+		spec.Translucent = true
+		if spec.AlphaSize < 8 {
+			spec.AlphaSize = 8
+		}
+	}
+	return spec
+}
+
+// alphaAttribs returns the surface attributes for premultiplied alpha,
+// nil if not wanted or not supported by the config.
+func (ctx *EGLContext) alphaAttribs() []EGLint {
+	if !ctx.translucent {
+		return nil
+	}
+	st, ok := GetConfigAttrib(ctx.display, ctx.config, SURFACE_TYPE)
+	if !ok || st&VG_ALPHA_FORMAT_PRE_BIT == 0 {
+		return nil
+	}
+	return []EGLint{ALPHA_FORMAT, ALPHA_FORMAT_PRE}
+}
+
+// IsTranslucent reports whether the surface has an alpha channel the
+// compositor can blend with: its config has alpha bits and either a
+// TRANSPARENT_RGB transparent type or a native visual with alpha. Native
+// visuals that cannot be decoded, such as X11 visual IDs, are assumed to
+// follow the config.
+func (ctx *EGLContext) IsTranslucent() bool {
+	if ctx.surface == NO_SURFACE {
+		return false
+	}
+	info, err := GetConfigInfo(ctx.display, ctx.config)
+	if err != nil || info.AlphaSize == 0 {
+		return false
+	}
+	if info.TransparentType == TRANSPARENT_RGB {
+		return true
+	}
+	alpha, known := visualAlpha(info.NativeVisualID)
+	return alpha || !known
+}
+
+// visualAlpha decodes a NATIVE_VISUAL_ID: an Android buffer format or a
+// DRM fourcc, as used by GBM and Wayland. known is false for other
+// visuals.
+func visualAlpha(id int) (alpha, known bool) {
+	if runtime.GOOS == "android" {
+		switch id {
+		case 1, 0x16, 0x2b: // RGBA_8888, RGBA_FP16, RGBA_1010102
+			return true, true
+		case 2, 3, 4: // RGBX_8888, RGB_888, RGB_565
+			return false, true
+		}
+		return false, false
+	}
+	var code [4]byte
+	for i := range code {
+		code[i] = byte(id >> (8 * i))
+		if code[i] < ' ' || code[i] > '~' {
+			return false, false
+		}
+	}
+	// AR24, AB24, RA24, BA24, AR30, ...
+	return code[0] == 'A' || code[1] == 'A', true
+}
+
+// IsPremultiplied reports whether the surface has the VG_ALPHA_FORMAT_PRE
+// alpha format. The alpha format only applies to OpenVG rendering; with
+// OpenGL ES the shaders decide what is written, and compositors generally
+// expect premultiplied alpha.
+func (ctx *EGLContext) IsPremultiplied() bool {
+	if ctx.surface == NO_SURFACE {
+		return false
+	}
+	format, ok := QuerySurface(ctx.display, ctx.surface, ALPHA_FORMAT)
+	return ok && format == ALPHA_FORMAT_PRE
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package egl_test
+
+import (
+	"testing"
+
+	"github.com/gooid/gl/egl"
+	"github.com/gooid/gl/egl/egltest"
+)
+
+func fourcc(s string) int {
+	return int(s[0]) | int(s[1])<<8 | int(s[2])<<16 | int(s[3])<<24
+}
+
+func TestSetTranslucentKeepsSpec(t *testing.T) {
+	b := egltest.New()
+	defer b.Install()()
+
+	ctx := egl.NewContext(egltest.Window(1), nil)
+	defer ctx.Terminate()
+	spec := ctx.ConfigSpec()
+	spec.AlphaSize = 1
+	ctx.SetConfigSpec(spec)
+
+	ctx.SetTranslucent(true)
+	if got := ctx.ConfigSpec().AlphaSize; got != 1 {
+		t.Errorf("alpha size = %d after SetTranslucent(true), want 1", got)
+	}
+	if !ctx.InitEGLSurface() {
+		t.Fatalf("InitEGLSurface: %v", ctx.Err())
+	}
+	if info, _ := ctx.ConfigInfo(); info.AlphaSize != 8 {
+		t.Errorf("config alpha size = %d, want 8", info.AlphaSize)
+	}
+
+	ctx.SetTranslucent(false)
+	if got := ctx.ConfigSpec(); got.AlphaSize != 1 || got.Translucent {
+		t.Errorf("spec alpha size %d, translucent %v after SetTranslucent(false), want 1, false",
+			got.AlphaSize, got.Translucent)
+	}
+}
+
+func TestIsTranslucent(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		visual      int
+		transparent int
+		want        bool
+	}{
+		{"alpha visual", fourcc("AR24"), egl.NONE, true},
+		{"opaque visual", fourcc("XR24"), egl.NONE, false},
+		{"transparent rgb", fourcc("XR24"), egl.TRANSPARENT_RGB, true},
+		{"unknown visual", 0x21, egl.NONE, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := egltest.New()
+			b.Configs = egltest.DefaultConfigs()[1:2] // 8/8/8/8
+			b.Configs[0][egl.NATIVE_VISUAL_ID] = tc.visual
+			b.Configs[0][egl.TRANSPARENT_TYPE] = tc.transparent
+			defer b.Install()()
+
+			ctx := egl.NewContext(egltest.Window(1), nil)
+			defer ctx.Terminate()
+			ctx.SetTranslucent(true)
+			if !ctx.InitEGLSurface() {
+				t.Fatalf("InitEGLSurface: %v", ctx.Err())
+			}
+			if got := ctx.IsTranslucent(); got != tc.want {
+				t.Errorf("IsTranslucent() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
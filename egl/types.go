@@ -43,9 +43,3 @@ func eglBool(n bool) C.EGLBoolean {
 	}
 	return C.EGLBoolean(b)
 }
-
-/*
-func ProcAdress(proc string) uintptr {
-
-}
-*/
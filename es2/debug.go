@@ -0,0 +1,230 @@
+package gl
+
+// #ifndef APIENTRY
+// #define APIENTRY
+// #endif
+// #ifndef APIENTRYP
+// #define APIENTRYP APIENTRY *
+// #endif
+// #include <stdint.h>
+// #include <stdlib.h>
+// typedef unsigned int GLenum;
+// typedef unsigned char GLboolean;
+// typedef unsigned int GLuint;
+// typedef int GLsizei;
+// typedef char GLchar;
+// typedef void (APIENTRY *GLDEBUGPROCKHR)(GLenum source,GLenum type,GLuint id,GLenum severity,GLsizei length,const GLchar *message,const void *userParam);
+// typedef void  (APIENTRYP GPDEBUGMESSAGECALLBACKKHR)(GLDEBUGPROCKHR  callback, const void * userParam);
+// typedef void  (APIENTRYP GPDEBUGMESSAGECONTROLKHR)(GLenum  source, GLenum  type, GLenum  severity, GLsizei  count, const GLuint * ids, GLboolean  enabled);
+// typedef void  (APIENTRYP GPOBJECTLABELKHR)(GLenum  identifier, GLuint  name, GLsizei  length, const GLchar * label);
+// extern void glowDebugCallback(GLenum source, GLenum type, GLuint id, GLenum severity, GLsizei length, GLchar *message);
+// static void APIENTRY glowDebugCallbackKHR(GLenum source, GLenum type, GLuint id, GLenum severity, GLsizei length, const GLchar *message, const void *userParam) {
+//   glowDebugCallback(source, type, id, severity, length, (GLchar *)message);
+// }
+// static void  glowDebugMessageCallbackKHR(GPDEBUGMESSAGECALLBACKKHR fnptr, GLboolean enable) {
+//   (*fnptr)(enable ? glowDebugCallbackKHR : NULL, NULL);
+// }
+// static void  glowDebugMessageControlKHR(GPDEBUGMESSAGECONTROLKHR fnptr, GLenum  source, GLenum  type, GLenum  severity, GLsizei  count, const GLuint * ids, GLboolean  enabled) {
+//   (*fnptr)(source, type, severity, count, ids, enabled);
+// }
+// static void  glowObjectLabelKHR(GPOBJECTLABELKHR fnptr, GLenum  identifier, GLuint  name, GLsizei  length, const GLchar * label) {
+//   (*fnptr)(identifier, name, length, label);
+// }
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// GL_KHR_debug
+const (
+	DEBUG_OUTPUT_KHR             = 0x92E0
+	DEBUG_OUTPUT_SYNCHRONOUS_KHR = 0x8242
+	MAX_DEBUG_MESSAGE_LENGTH_KHR = 0x9143
+	MAX_LABEL_LENGTH_KHR         = 0x82E8
+	CONTEXT_FLAG_DEBUG_BIT_KHR   = 0x00000002
+	BUFFER_KHR                   = 0x82E0
+	SHADER_KHR                   = 0x82E1
+	PROGRAM_KHR                  = 0x82E2
+	VERTEX_ARRAY_KHR             = 0x8074
+	QUERY_KHR                    = 0x82E3
+	PROGRAM_PIPELINE_KHR         = 0x82E4
+	SAMPLER_KHR                  = 0x82E6
+)
+
+// DebugSource is the origin of a debug message.
+type DebugSource uint32
+
+const (
+	DEBUG_SOURCE_API_KHR             DebugSource = 0x8246
+	DEBUG_SOURCE_WINDOW_SYSTEM_KHR   DebugSource = 0x8247
+	DEBUG_SOURCE_SHADER_COMPILER_KHR DebugSource = 0x8248
+	DEBUG_SOURCE_THIRD_PARTY_KHR     DebugSource = 0x8249
+	DEBUG_SOURCE_APPLICATION_KHR     DebugSource = 0x824A
+	DEBUG_SOURCE_OTHER_KHR           DebugSource = 0x824B
+)
+
+// DebugType is the kind of a debug message.
+type DebugType uint32
+
+const (
+	DEBUG_TYPE_ERROR_KHR               DebugType = 0x824C
+	DEBUG_TYPE_DEPRECATED_BEHAVIOR_KHR DebugType = 0x824D
+	DEBUG_TYPE_UNDEFINED_BEHAVIOR_KHR  DebugType = 0x824E
+	DEBUG_TYPE_PORTABILITY_KHR         DebugType = 0x824F
+	DEBUG_TYPE_PERFORMANCE_KHR         DebugType = 0x8250
+	DEBUG_TYPE_OTHER_KHR               DebugType = 0x8251
+	DEBUG_TYPE_MARKER_KHR              DebugType = 0x8268
+	DEBUG_TYPE_PUSH_GROUP_KHR          DebugType = 0x8269
+	DEBUG_TYPE_POP_GROUP_KHR           DebugType = 0x826A
+)
+
+// DebugSeverity is the importance of a debug message.
+type DebugSeverity uint32
+
+const (
+	DEBUG_SEVERITY_HIGH_KHR         DebugSeverity = 0x9146
+	DEBUG_SEVERITY_MEDIUM_KHR       DebugSeverity = 0x9147
+	DEBUG_SEVERITY_LOW_KHR          DebugSeverity = 0x9148
+	DEBUG_SEVERITY_NOTIFICATION_KHR DebugSeverity = 0x826B
+)
+
+func (s DebugSource) String() string {
+	switch s {
+	case DEBUG_SOURCE_API_KHR:
+		return "api"
+	case DEBUG_SOURCE_WINDOW_SYSTEM_KHR:
+		return "window system"
+	case DEBUG_SOURCE_SHADER_COMPILER_KHR:
+		return "shader compiler"
+	case DEBUG_SOURCE_THIRD_PARTY_KHR:
+		return "third party"
+	case DEBUG_SOURCE_APPLICATION_KHR:
+		return "application"
+	case DEBUG_SOURCE_OTHER_KHR:
+		return "other"
+	}
+	return fmt.Sprintf("source 0x%x", uint32(s))
+}
+
+func (t DebugType) String() string {
+	switch t {
+	case DEBUG_TYPE_ERROR_KHR:
+		return "error"
+	case DEBUG_TYPE_DEPRECATED_BEHAVIOR_KHR:
+		return "deprecated behavior"
+	case DEBUG_TYPE_UNDEFINED_BEHAVIOR_KHR:
+		return "undefined behavior"
+	case DEBUG_TYPE_PORTABILITY_KHR:
+		return "portability"
+	case DEBUG_TYPE_PERFORMANCE_KHR:
+		return "performance"
+	case DEBUG_TYPE_OTHER_KHR:
+		return "other"
+	case DEBUG_TYPE_MARKER_KHR:
+		return "marker"
+	case DEBUG_TYPE_PUSH_GROUP_KHR:
+		return "push group"
+	case DEBUG_TYPE_POP_GROUP_KHR:
+		return "pop group"
+	}
+	return fmt.Sprintf("type 0x%x", uint32(t))
+}
+
+func (s DebugSeverity) String() string {
+	switch s {
+	case DEBUG_SEVERITY_HIGH_KHR:
+		return "high"
+	case DEBUG_SEVERITY_MEDIUM_KHR:
+		return "medium"
+	case DEBUG_SEVERITY_LOW_KHR:
+		return "low"
+	case DEBUG_SEVERITY_NOTIFICATION_KHR:
+		return "notification"
+	}
+	return fmt.Sprintf("severity 0x%x", uint32(s))
+}
+
+// DebugMessage is a message of the GL implementation or the application.
+type DebugMessage struct {
+	Source   DebugSource
+	Type     DebugType
+	ID       uint32
+	Severity DebugSeverity
+	Message  string
+}
+
+func (m DebugMessage) String() string {
+	return fmt.Sprintf("%v %v %v (%d): %s", m.Severity, m.Source, m.Type, m.ID, m.Message)
+}
+
+// DebugCallback receives debug messages. Unless DEBUG_OUTPUT_SYNCHRONOUS_KHR
+// is enabled it may be called on any thread, concurrently with GL calls.
+type DebugCallback func(DebugMessage)
+
+var (
+	gpDebugMessageCallbackKHR    C.GPDEBUGMESSAGECALLBACKKHR
+	gpDebugMessageControlKHR     C.GPDEBUGMESSAGECONTROLKHR
+	gpObjectLabelKHR             C.GPOBJECTLABELKHR
+	errNoDebugMessageCallbackKHR = errors.New("es2: glDebugMessageCallbackKHR is not available")
+	errNoDebugMessageControlKHR  = errors.New("es2: glDebugMessageControlKHR is not available")
+	errNoObjectLabelKHR          = errors.New("es2: glObjectLabelKHR is not available")
+
+	debugMu       sync.RWMutex
+	debugCallback DebugCallback
+)
+
+func initDebug(getProcAddr func(name string) unsafe.Pointer) {
+	gpDebugMessageCallbackKHR = (C.GPDEBUGMESSAGECALLBACKKHR)(loadProc(getProcAddr, "glDebugMessageCallbackKHR"))
+	gpDebugMessageControlKHR = (C.GPDEBUGMESSAGECONTROLKHR)(loadProc(getProcAddr, "glDebugMessageControlKHR"))
+	gpObjectLabelKHR = (C.GPOBJECTLABELKHR)(loadProc(getProcAddr, "glObjectLabelKHR"))
+}
+
+// DebugMessageCallbackKHR routes the debug messages of the current
+// context to cb, nil to stop (GL_KHR_debug). There is a single callback
+// for the whole process. Messages are only generated with DEBUG_OUTPUT_KHR
+// enabled, which is the default for debug contexts.
+func DebugMessageCallbackKHR(cb DebugCallback) error {
+	if gpDebugMessageCallbackKHR == nil {
+		return errNoDebugMessageCallbackKHR
+	}
+	debugMu.Lock()
+	debugCallback = cb
+	debugMu.Unlock()
+	C.glowDebugMessageCallbackKHR(gpDebugMessageCallbackKHR, (C.GLboolean)(boolToInt(cb != nil)))
+	return nil
+}
+
+// DebugMessageControlKHR enables or disables the messages matching source,
+// xtype and severity, each of which can be DONT_CARE, and, if ids is not
+// empty, one of the ids.
+func DebugMessageControlKHR(source DebugSource, xtype DebugType, severity DebugSeverity, ids []uint32, enabled bool) error {
+	if gpDebugMessageControlKHR == nil {
+		return errNoDebugMessageControlKHR
+	}
+	var pids *C.GLuint
+	if len(ids) > 0 {
+		pids = (*C.GLuint)(unsafe.Pointer(&ids[0]))
+	}
+	C.glowDebugMessageControlKHR(gpDebugMessageControlKHR, (C.GLenum)(source), (C.GLenum)(xtype), (C.GLenum)(severity), (C.GLsizei)(len(ids)), pids, (C.GLboolean)(boolToInt(enabled)))
+	return nil
+}
+
+// ObjectLabelKHR names the object name of kind identifier (BUFFER_KHR,
+// SHADER_KHR, PROGRAM_KHR, TEXTURE, FRAMEBUFFER, ...) in debug messages
+// and tools. An empty label removes the label.
+func ObjectLabelKHR(identifier, name uint32, label string) error {
+	if gpObjectLabelKHR == nil {
+		return errNoObjectLabelKHR
+	}
+	var clabel *C.char
+	if label != "" {
+		clabel = C.CString(label)
+		defer C.free(unsafe.Pointer(clabel))
+	}
+	C.glowObjectLabelKHR(gpObjectLabelKHR, (C.GLenum)(identifier), (C.GLuint)(name), (C.GLsizei)(len(label)), (*C.GLchar)(unsafe.Pointer(clabel)))
+	return nil
+}
@@ -0,0 +1,24 @@
+package gl
+
+// typedef unsigned int GLenum;
+// typedef unsigned int GLuint;
+// typedef int GLsizei;
+// typedef char GLchar;
+import "C"
+
+//export glowDebugCallback
+func glowDebugCallback(source, xtype C.GLenum, id C.GLuint, severity C.GLenum, length C.GLsizei, message *C.GLchar) {
+	debugMu.RLock()
+	cb := debugCallback
+	debugMu.RUnlock()
+	if cb == nil {
+		return
+	}
+	cb(DebugMessage{
+		Source:   DebugSource(source),
+		Type:     DebugType(xtype),
+		ID:       uint32(id),
+		Severity: DebugSeverity(severity),
+		Message:  C.GoStringN(message, C.int(length)),
+	})
+}
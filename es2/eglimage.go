@@ -0,0 +1,50 @@
+package gl
+
+// #ifndef APIENTRY
+// #define APIENTRY
+// #endif
+// #ifndef APIENTRYP
+// #define APIENTRYP APIENTRY *
+// #endif
+// typedef unsigned int GLenum;
+// typedef void  (APIENTRYP GPEGLIMAGETARGETTEXTURE2DOES)(GLenum  target, void * image);
+// static void  glowEGLImageTargetTexture2DOES(GPEGLIMAGETARGETTEXTURE2DOES fnptr, GLenum  target, void * image) {
+//   (*fnptr)(target, image);
+// }
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+const (
+	TEXTURE_EXTERNAL_OES = 0x8D65
+)
+
+var (
+	gpEGLImageTargetTexture2DOES C.GPEGLIMAGETARGETTEXTURE2DOES
+	errNoEGLImageTargetTexture2D = errors.New("es2: glEGLImageTargetTexture2DOES is not available")
+)
+
+func initEGLImage(getProcAddr func(name string) unsafe.Pointer) {
+	gpEGLImageTargetTexture2DOES = (C.GPEGLIMAGETARGETTEXTURE2DOES)(loadProc(getProcAddr, "glEGLImageTargetTexture2DOES"))
+}
+
+// EGLImageTargetTexture2DOES defines the image of the texture bound to
+// target (TEXTURE_2D or TEXTURE_EXTERNAL_OES) as the EGLImage image
+// (GL_OES_EGL_image). It fails if the entry point is not available.
+func EGLImageTargetTexture2DOES(target uint32, image unsafe.Pointer) error {
+	if gpEGLImageTargetTexture2DOES == nil {
+		return errNoEGLImageTargetTexture2D
+	}
+	C.glowEGLImageTargetTexture2DOES(gpEGLImageTargetTexture2DOES, (C.GLenum)(target), image)
+	return nil
+}
+
+// BindEGLImage binds the texture to target and makes the EGLImage image
+// its storage, see EGLImageTargetTexture2DOES.
+func (texture Texture) BindEGLImage(target uint32, image unsafe.Pointer) error {
+	BindTexture(target, texture.c())
+	return EGLImageTargetTexture2DOES(target, image)
+}
@@ -0,0 +1,21 @@
+package gl
+
+import "unsafe"
+
+// initExtensions loads the entry points of the extensions wrapped by
+// hand. They are optional: the wrappers fail while theirs is missing.
+func initExtensions(getProcAddr func(name string) unsafe.Pointer) {
+	initDebug(getProcAddr)
+	initEGLImage(getProcAddr)
+	initRobustness(getProcAddr)
+}
+
+// loadProc returns the entry point name from getProcAddr and logs when it
+// is missing.
+func loadProc(getProcAddr func(name string) unsafe.Pointer, name string) unsafe.Pointer {
+	p := getProcAddr(name)
+	if p == nil {
+		Logger().Debug("missing GL entry point", "func", name)
+	}
+	return p
+}
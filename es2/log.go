@@ -0,0 +1,21 @@
+package gl
+
+import (
+	"log/slog"
+
+	"github.com/gooid/gl/internal/logging"
+)
+
+var logger logging.Hook
+
+// SetLogger sets the logger used by the hand written parts of the
+// package, e.g. to report missing extension entry points. A nil logger,
+// the default, discards everything.
+func SetLogger(l *slog.Logger) {
+	logger.Set(l)
+}
+
+// Logger returns the logger set by SetLogger.
+func Logger() *slog.Logger {
+	return logger.Get()
+}
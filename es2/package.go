@@ -2345,5 +2345,6 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpViewport == nil {
 		return errors.New("glViewport")
 	}
+	initExtensions(getProcAddr)
 	return nil
 }
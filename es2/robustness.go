@@ -0,0 +1,51 @@
+package gl
+
+// #ifndef APIENTRY
+// #define APIENTRY
+// #endif
+// #ifndef APIENTRYP
+// #define APIENTRYP APIENTRY *
+// #endif
+// typedef unsigned int GLenum;
+// typedef GLenum  (APIENTRYP GPGETGRAPHICSRESETSTATUSEXT)();
+// static GLenum  glowGetGraphicsResetStatusEXT(GPGETGRAPHICSRESETSTATUSEXT fnptr) {
+//   return (*fnptr)();
+// }
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// GL_EXT_robustness
+const (
+	GUILTY_CONTEXT_RESET_EXT        = 0x8253
+	INNOCENT_CONTEXT_RESET_EXT      = 0x8254
+	UNKNOWN_CONTEXT_RESET_EXT       = 0x8255
+	CONTEXT_ROBUST_ACCESS_EXT       = 0x90F3
+	RESET_NOTIFICATION_STRATEGY_EXT = 0x8256
+	LOSE_CONTEXT_ON_RESET_EXT       = 0x8252
+	NO_RESET_NOTIFICATION_EXT       = 0x8261
+)
+
+var (
+	gpGetGraphicsResetStatusEXT    C.GPGETGRAPHICSRESETSTATUSEXT
+	errNoGetGraphicsResetStatusEXT = errors.New("es2: glGetGraphicsResetStatusEXT is not available")
+)
+
+func initRobustness(getProcAddr func(name string) unsafe.Pointer) {
+	gpGetGraphicsResetStatusEXT = (C.GPGETGRAPHICSRESETSTATUSEXT)(loadProc(getProcAddr, "glGetGraphicsResetStatusEXT"))
+}
+
+// GetGraphicsResetStatusEXT reports whether the context has been reset
+// since the last call: NO_ERROR, or GUILTY_CONTEXT_RESET_EXT,
+// INNOCENT_CONTEXT_RESET_EXT or UNKNOWN_CONTEXT_RESET_EXT after a reset,
+// after which the context must be recreated (GL_EXT_robustness). It fails
+// if the entry point is not available.
+func GetGraphicsResetStatusEXT() (uint32, error) {
+	if gpGetGraphicsResetStatusEXT == nil {
+		return NO_ERROR, errNoGetGraphicsResetStatusEXT
+	}
+	return uint32(C.glowGetGraphicsResetStatusEXT(gpGetGraphicsResetStatusEXT)), nil
+}
@@ -0,0 +1,3 @@
+module github.com/gooid/gl
+
+go 1.21
@@ -0,0 +1,45 @@
+// Copyright 2018 The Gooid Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package logging holds the logger plumbing shared by the egl and es2
+// packages.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Hook is the settable logger of a package. The zero value discards
+// everything.
+type Hook struct {
+	l atomic.Pointer[slog.Logger]
+}
+
+var discard = slog.New(discardHandler{})
+
+// Set replaces the logger, nil to discard everything.
+func (h *Hook) Set(l *slog.Logger) {
+	if l == nil {
+		l = discard
+	}
+	h.l.Store(l)
+}
+
+// Get returns the logger passed to Set.
+func (h *Hook) Get() *slog.Logger {
+	if l := h.l.Load(); l != nil {
+		return l
+	}
+	return discard
+}
+
+// discardHandler is a slog.Handler that drops every record.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }